@@ -5,8 +5,12 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	gotypes "go/types"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/navyarakshakarya/code-gen/logger"
@@ -18,22 +22,49 @@ type Analyzer struct {
 	logger    *logger.Logger
 	fileSet   *token.FileSet
 	buildTags []string
+	options   Options
+	methods   map[string][]types.MethodInfo // receiver type key -> exported methods
 }
 
+// Options configure which files the analyzer reads
+type Options struct {
+	// Tags are the build tags files must match to be analyzed
+	Tags []string
+
+	// Skip lists the paths not analyzed: entries ending in "/" match
+	// directories by name anywhere in the project, other entries are globs
+	// matched against file names, or against slash-separated paths relative
+	// to the project when they contain a "/". Nil means DefaultSkip.
+	Skip []string
+
+	// Gitignore skips files ignored by the project's .gitignore files
+	Gitignore bool
+
+	// Tests analyzes _test.go files, for generating test doubles of
+	// interfaces declared in tests
+	Tests bool
+}
+
+// DefaultSkip is the skip list used when Options.Skip is nil
+var DefaultSkip = []string{".git/", "vendor/", "testdata/", "*.gen.go"}
+
 // New creates a new analyzer instance
-func New(logger *logger.Logger, tags string) *Analyzer {
+func New(logger *logger.Logger, options Options) *Analyzer {
 	var buildTags []string
-	if tags != "" {
-		buildTags = strings.Split(tags, ",")
-		for i, tag := range buildTags {
-			buildTags[i] = strings.TrimSpace(tag)
+	for _, tag := range options.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			buildTags = append(buildTags, tag)
 		}
 	}
+	if options.Skip == nil {
+		options.Skip = DefaultSkip
+	}
 
 	return &Analyzer{
 		logger:    logger,
 		fileSet:   token.NewFileSet(),
 		buildTags: buildTags,
+		options:   options,
 	}
 }
 
@@ -42,9 +73,13 @@ func (a *Analyzer) AnalyzeProject(projectDir string) (*types.ProjectInfo, error)
 	projectInfo := &types.ProjectInfo{
 		Interfaces: make(map[string]*types.InterfaceInfo),
 		Structs:    make(map[string]*types.StructInfo),
+		Types:      make(map[string]*types.TypeInfo),
+		Functions:  make(map[string]*types.FuncInfo),
 		Imports:    make(map[string]string),
 		ProjectDir: projectDir,
+		Packages:   make(map[string]string),
 	}
+	a.methods = make(map[string][]types.MethodInfo)
 
 	// Get module information
 	moduleName, err := a.getModuleName(projectDir)
@@ -53,19 +88,41 @@ func (a *Analyzer) AnalyzeProject(projectDir string) (*types.ProjectInfo, error)
 	}
 	projectInfo.ModuleName = moduleName
 
+	var ignore gitignore
+
 	// Parse all Go files in the project
 	err = filepath.Walk(projectDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// Skip non-Go files and certain directories
+		relPath, err := filepath.Rel(projectDir, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if info.IsDir() {
+			if relPath == "." {
+				return a.loadGitignore(&ignore, path, "")
+			}
+
+			// Nested modules have their own import paths and are analyzed separately
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				a.logger.Info("Skipping nested module: %s", path)
+				return filepath.SkipDir
+			}
+			if a.skipped(relPath, true) || (a.options.Gitignore && ignore.ignored(relPath, true)) {
+				return filepath.SkipDir
+			}
+			return a.loadGitignore(&ignore, path, relPath)
+		}
+
+		// Skip non-Go files, test files and the configured paths
 		if !strings.HasSuffix(path, ".go") ||
-			strings.HasSuffix(path, "_test.go") ||
-			strings.HasSuffix(path, ".gen.go") ||
-			strings.Contains(path, "vendor/") ||
-			strings.Contains(path, ".git/") ||
-			strings.Contains(path, "testdata/") {
+			(strings.HasSuffix(path, "_test.go") && !a.options.Tests) ||
+			a.skipped(relPath, false) ||
+			(a.options.Gitignore && ignore.ignored(relPath, false)) {
 			return nil
 		}
 
@@ -76,6 +133,16 @@ func (a *Analyzer) AnalyzeProject(projectDir string) (*types.ProjectInfo, error)
 		return nil, err
 	}
 
+	// Projects without Go files at the root are named after the module
+	if projectInfo.PackageName == "" {
+		projectInfo.PackageName = packageNameFromModule(moduleName)
+	}
+
+	// Attach methods to their receiver structs
+	for key, structInfo := range projectInfo.Structs {
+		structInfo.Methods = a.methods[key]
+	}
+
 	// Post-process to establish relationships
 	a.establishRelationships(projectInfo)
 
@@ -121,13 +188,21 @@ func (a *Analyzer) analyzeFile(filePath string, projectInfo *types.ProjectInfo)
 	}
 
 	packageName := file.Name.Name
-	if projectInfo.PackageName == "" {
-		projectInfo.PackageName = packageName
-	}
 
+	// File paths are recorded slash-separated on every platform
 	relPath, _ := filepath.Rel(projectInfo.ProjectDir, filePath)
+	relPath = filepath.ToSlash(relPath)
+
+	dir := path.Dir(relPath)
+	if _, exists := projectInfo.Packages[dir]; !exists {
+		projectInfo.Packages[dir] = packageName
+	}
+	if dir == "." {
+		projectInfo.PackageName = packageName
+	}
 
 	// Extract imports
+	fileImports := make(map[string]string)
 	for _, imp := range file.Imports {
 		if imp.Path != nil {
 			importPath := strings.Trim(imp.Path.Value, `"`)
@@ -140,6 +215,7 @@ func (a *Analyzer) analyzeFile(filePath string, projectInfo *types.ProjectInfo)
 				alias = parts[len(parts)-1]
 			}
 			projectInfo.Imports[alias] = importPath
+			fileImports[alias] = importPath
 		}
 	}
 
@@ -148,7 +224,13 @@ func (a *Analyzer) analyzeFile(filePath string, projectInfo *types.ProjectInfo)
 		switch node := n.(type) {
 		case *ast.GenDecl:
 			if node.Tok == token.TYPE {
-				a.processTypeDeclaration(node, packageName, relPath, projectInfo)
+				a.processTypeDeclaration(node, packageName, relPath, fileImports, projectInfo)
+			}
+		case *ast.FuncDecl:
+			if node.Recv == nil && strings.HasPrefix(node.Name.Name, "New") {
+				a.extractConstructor(node, packageName, relPath, fileImports, projectInfo)
+			} else if node.Recv != nil && node.Name.IsExported() {
+				a.extractReceiverMethod(node, dir)
 			}
 		}
 		return true
@@ -191,32 +273,39 @@ func (a *Analyzer) shouldIncludeFile(filePath string) bool {
 }
 
 // processTypeDeclaration processes type declarations
-func (a *Analyzer) processTypeDeclaration(genDecl *ast.GenDecl, packageName, filePath string, projectInfo *types.ProjectInfo) {
+func (a *Analyzer) processTypeDeclaration(genDecl *ast.GenDecl, packageName, filePath string, fileImports map[string]string, projectInfo *types.ProjectInfo) {
 	for _, spec := range genDecl.Specs {
 		typeSpec, ok := spec.(*ast.TypeSpec)
 		if !ok {
 			continue
 		}
 
-		// Extract comments
-		var comments []string
-		if genDecl.Doc != nil {
-			for _, comment := range genDecl.Doc.List {
-				comments = append(comments, strings.TrimPrefix(comment.Text, "//"))
-			}
+		// Extract comments; specs of a grouped declaration carry their own
+		doc := typeSpec.Doc
+		if doc == nil && len(genDecl.Specs) == 1 {
+			doc = genDecl.Doc
 		}
+		comments := docComments(doc)
 
 		switch t := typeSpec.Type.(type) {
 		case *ast.InterfaceType:
-			a.extractInterface(typeSpec.Name.Name, t, packageName, filePath, comments, projectInfo)
+			a.extractInterface(typeSpec.Name.Name, t, packageName, filePath, comments, fileImports, projectInfo)
 		case *ast.StructType:
 			a.extractStruct(typeSpec.Name.Name, t, packageName, filePath, comments, projectInfo)
+		default:
+			// Recorded so generated code can compute zero values of named types
+			projectInfo.Types[declKey(filePath, typeSpec.Name.Name)] = &types.TypeInfo{
+				Name:       typeSpec.Name.Name,
+				Package:    packageName,
+				FilePath:   filePath,
+				Underlying: a.typeToString(t),
+			}
 		}
 	}
 }
 
 // extractInterface extracts interface information
-func (a *Analyzer) extractInterface(name string, iface *ast.InterfaceType, pkg, filePath string, comments []string, projectInfo *types.ProjectInfo) {
+func (a *Analyzer) extractInterface(name string, iface *ast.InterfaceType, pkg, filePath string, comments []string, fileImports map[string]string, projectInfo *types.ProjectInfo) {
 	interfaceInfo := &types.InterfaceInfo{
 		Name:     name,
 		Package:  pkg,
@@ -224,6 +313,7 @@ func (a *Analyzer) extractInterface(name string, iface *ast.InterfaceType, pkg,
 		Methods:  []types.MethodInfo{},
 		Layer:    a.determineLayer(name),
 		Comments: comments,
+		Imports:  fileImports,
 	}
 
 	// Extract methods
@@ -231,13 +321,19 @@ func (a *Analyzer) extractInterface(name string, iface *ast.InterfaceType, pkg,
 		if funcType, ok := method.Type.(*ast.FuncType); ok {
 			for _, methodName := range method.Names {
 				methodInfo := a.extractMethodInfo(methodName.Name, funcType)
+				methodInfo.Comments = docComments(method.Doc)
+				methodInfo.Skip = hasDirective(method.Doc, skipDirective) || hasDirective(method.Comment, skipDirective)
 				interfaceInfo.Methods = append(interfaceInfo.Methods, methodInfo)
 			}
 		}
 	}
 
-	projectInfo.Interfaces[name] = interfaceInfo
-	a.logger.Info("Found interface: %s (%s layer)", name, interfaceInfo.Layer)
+	key := declKey(filePath, name)
+	if existing, exists := projectInfo.Interfaces[key]; exists {
+		a.logger.Warning("Interface %s in %s is also declared in %s, using the latter", name, existing.FilePath, filePath)
+	}
+	projectInfo.Interfaces[key] = interfaceInfo
+	a.logger.Info("Found interface: %s (%s layer)", key, interfaceInfo.Layer)
 }
 
 // extractStruct extracts struct information
@@ -278,7 +374,45 @@ func (a *Analyzer) extractStruct(name string, structType *ast.StructType, pkg, f
 		}
 	}
 
-	projectInfo.Structs[name] = structInfo
+	projectInfo.Structs[declKey(filePath, name)] = structInfo
+}
+
+// extractConstructor records a top-level New* function so dependencies can be
+// inferred from its parameters
+func (a *Analyzer) extractConstructor(funcDecl *ast.FuncDecl, pkg, filePath string, fileImports map[string]string, projectInfo *types.ProjectInfo) {
+	signature := a.extractMethodInfo(funcDecl.Name.Name, funcDecl.Type)
+
+	key := declKey(filePath, funcDecl.Name.Name)
+	projectInfo.Functions[key] = &types.FuncInfo{
+		Name:     funcDecl.Name.Name,
+		Package:  pkg,
+		FilePath: filePath,
+		Params:   signature.Params,
+		Returns:  signature.Returns,
+		Imports:  fileImports,
+	}
+	a.logger.Info("Found constructor: %s", key)
+}
+
+// extractReceiverMethod records an exported method under the key of its
+// receiver type in the package in dir
+func (a *Analyzer) extractReceiverMethod(funcDecl *ast.FuncDecl, dir string) {
+	if len(funcDecl.Recv.List) == 0 {
+		return
+	}
+
+	receiver := strings.TrimPrefix(a.typeToString(funcDecl.Recv.List[0].Type), "*")
+	method := a.extractMethodInfo(funcDecl.Name.Name, funcDecl.Type)
+	method.Comments = docComments(funcDecl.Doc)
+
+	key := types.Key(dir, receiver)
+	a.methods[key] = append(a.methods[key], method)
+}
+
+// declKey returns the key of a declaration named name in the file at the
+// slash-separated filePath
+func declKey(filePath, name string) string {
+	return types.Key(path.Dir(filePath), name)
 }
 
 // extractMethodInfo extracts method information from function type
@@ -344,30 +478,62 @@ func (a *Analyzer) extractMethodInfo(name string, funcType *ast.FuncType) types.
 	return method
 }
 
-// typeToString converts AST type to string representation
+// typeToString converts AST type to its Go source representation, including
+// full function signatures, channel directions and array lengths
 func (a *Analyzer) typeToString(expr ast.Expr) string {
-	switch t := expr.(type) {
-	case *ast.Ident:
-		return t.Name
-	case *ast.SelectorExpr:
-		return a.typeToString(t.X) + "." + t.Sel.Name
-	case *ast.StarExpr:
-		return "*" + a.typeToString(t.X)
-	case *ast.ArrayType:
-		return "[]" + a.typeToString(t.Elt)
-	case *ast.MapType:
-		return "map[" + a.typeToString(t.Key) + "]" + a.typeToString(t.Value)
-	case *ast.InterfaceType:
-		return "interface{}"
-	case *ast.ChanType:
-		return "chan " + a.typeToString(t.Value)
-	case *ast.FuncType:
-		return "func(...)"
-	case *ast.Ellipsis:
-		return "..." + a.typeToString(t.Elt)
-	default:
-		return "interface{}"
+	return gotypes.ExprString(expr)
+}
+
+// skipDirective marks interface methods code-gen must not implement
+const skipDirective = "codegen:skip"
+
+// directive matches directive comments such as //go:generate or //codegen:skip
+var directive = regexp.MustCompile(`^//[a-z0-9]+:[a-z0-9]`)
+
+// docComments returns the lines of a doc comment without comment markers and
+// directives
+func docComments(group *ast.CommentGroup) []string {
+	if group == nil {
+		return nil
+	}
+
+	var lines []string
+	for _, comment := range group.List {
+		if directive.MatchString(comment.Text) {
+			continue
+		}
+		if text, ok := strings.CutPrefix(comment.Text, "/*"); ok {
+			lines = append(lines, strings.Split(strings.TrimSuffix(text, "*/"), "\n")...)
+			continue
+		}
+		lines = append(lines, strings.TrimPrefix(comment.Text, "//"))
 	}
+	return lines
+}
+
+// hasDirective reports whether a comment of the group starts with //name
+func hasDirective(group *ast.CommentGroup, name string) bool {
+	if group == nil {
+		return false
+	}
+	for _, comment := range group.List {
+		if fields := strings.Fields(strings.TrimPrefix(comment.Text, "//")); len(fields) > 0 && fields[0] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// packageNameFromModule derives a package name from the last element of a
+// module path
+func packageNameFromModule(moduleName string) string {
+	name := strings.ToLower(path.Base(moduleName))
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' {
+			return '_'
+		}
+		return r
+	}, name)
 }
 
 // determineLayer determines the architectural layer based on interface name
@@ -389,16 +555,17 @@ func (a *Analyzer) determineLayer(interfaceName string) types.LayerType {
 
 // establishRelationships finds relationships between interfaces
 func (a *Analyzer) establishRelationships(projectInfo *types.ProjectInfo) {
-	for _, interfaceInfo := range projectInfo.Interfaces {
+	for key, interfaceInfo := range projectInfo.Interfaces {
 		baseName := a.extractBaseName(interfaceInfo.Name)
 
 		// Find related interfaces with same base name
-		for _, otherInterface := range projectInfo.Interfaces {
+		for otherKey, otherInterface := range projectInfo.Interfaces {
 			otherBaseName := a.extractBaseName(otherInterface.Name)
-			if baseName == otherBaseName && interfaceInfo.Name != otherInterface.Name {
-				interfaceInfo.RelatedInterfaces = append(interfaceInfo.RelatedInterfaces, otherInterface.Name)
+			if baseName == otherBaseName && key != otherKey {
+				interfaceInfo.RelatedInterfaces = append(interfaceInfo.RelatedInterfaces, otherKey)
 			}
 		}
+		sort.Strings(interfaceInfo.RelatedInterfaces)
 	}
 }
 
@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/navyarakshakarya/code-gen/logger"
+)
+
+// writeProject writes files, keyed by slash-separated path, into a new
+// temporary project directory
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestAnalyzeProjectSameNameInDifferentPackages(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.24\n",
+		"internal/user/user.go": `package user
+
+type User struct {
+	ID int64
+}
+
+func (u *User) Validate() error { return nil }
+
+type Repository interface {
+	Get(id int64) (*User, error)
+}
+
+type UserRepo interface {
+	Count() (int, error)
+}
+
+func NewRepository() Repository { return nil }
+`,
+		"internal/order/order.go": `package order
+
+type User struct {
+	Name string
+}
+
+type Repository interface {
+	Get(id int64) (*User, error)
+	Delete(id int64) error
+}
+
+type OrderRepo interface {
+	Count() (int, error)
+}
+
+func NewRepository() Repository { return nil }
+`,
+	})
+
+	projectInfo, err := New(logger.New(false, true), Options{}).AnalyzeProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key     string
+		name    string
+		pkg     string
+		methods int
+	}{
+		{"internal/user.Repository", "Repository", "user", 1},
+		{"internal/user.UserRepo", "UserRepo", "user", 1},
+		{"internal/order.Repository", "Repository", "order", 2},
+		{"internal/order.OrderRepo", "OrderRepo", "order", 1},
+	}
+	if len(projectInfo.Interfaces) != len(tests) {
+		var keys []string
+		for key := range projectInfo.Interfaces {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		t.Fatalf("found interfaces %v, want %d", keys, len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			interfaceInfo, exists := projectInfo.Interfaces[tt.key]
+			if !exists {
+				t.Fatalf("interface %s not found", tt.key)
+			}
+			if interfaceInfo.Name != tt.name || interfaceInfo.Package != tt.pkg || len(interfaceInfo.Methods) != tt.methods {
+				t.Errorf("got %s in package %s with %d methods, want %s in package %s with %d methods",
+					interfaceInfo.Name, interfaceInfo.Package, len(interfaceInfo.Methods), tt.name, tt.pkg, tt.methods)
+			}
+		})
+	}
+
+	// Structs, their methods and constructors are kept apart the same way
+	userStruct, exists := projectInfo.Structs["internal/user.User"]
+	if !exists || len(userStruct.Fields) != 1 || userStruct.Fields[0].Name != "ID" || len(userStruct.Methods) != 1 {
+		t.Errorf("internal/user.User = %+v, want one ID field and one method", userStruct)
+	}
+	orderStruct, exists := projectInfo.Structs["internal/order.User"]
+	if !exists || len(orderStruct.Fields) != 1 || orderStruct.Fields[0].Name != "Name" || len(orderStruct.Methods) != 0 {
+		t.Errorf("internal/order.User = %+v, want one Name field and no methods", orderStruct)
+	}
+	for _, key := range []string{"internal/user.NewRepository", "internal/order.NewRepository"} {
+		if _, exists := projectInfo.Functions[key]; !exists {
+			t.Errorf("constructor %s not found", key)
+		}
+	}
+}
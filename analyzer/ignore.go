@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// skipped reports whether the slash-separated relPath matches the skip list
+func (a *Analyzer) skipped(relPath string, isDir bool) bool {
+	for _, pattern := range a.options.Skip {
+		if dir, ok := strings.CutSuffix(pattern, "/"); ok {
+			if isDir && matchGlob(dir, path.Base(relPath)) {
+				return true
+			}
+			continue
+		}
+		if isDir {
+			continue
+		}
+
+		name := path.Base(relPath)
+		if strings.Contains(pattern, "/") {
+			name = relPath
+		}
+		if matchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether name matches pattern, treating malformed
+// patterns as not matching
+func matchGlob(pattern, name string) bool {
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// gitignoreRule is a single pattern of a .gitignore file
+type gitignoreRule struct {
+	base    string // directory of the .gitignore file, relative to the project
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// gitignore holds the rules of the .gitignore files found so far, in the
+// order later rules take precedence
+type gitignore struct {
+	rules []gitignoreRule
+}
+
+// loadGitignore adds the rules of the .gitignore file in dir, if any
+func (a *Analyzer) loadGitignore(ignore *gitignore, dir, relDir string) error {
+	if !a.options.Gitignore {
+		return nil
+	}
+
+	file, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read .gitignore: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseGitignoreLine(scanner.Text(), relDir); ok {
+			ignore.rules = append(ignore.rules, rule)
+		}
+	}
+	return scanner.Err()
+}
+
+// parseGitignoreLine converts a .gitignore line into a rule
+func parseGitignoreLine(line, base string) (gitignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return gitignoreRule{}, false
+	}
+
+	rule := gitignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`)
+	if trimmed, ok := strings.CutSuffix(line, "/"); ok {
+		rule.dirOnly = true
+		line = trimmed
+	}
+
+	// Patterns containing a slash are relative to the .gitignore directory;
+	// others match at any depth
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return gitignoreRule{}, false
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case strings.HasPrefix(line[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(line[i:], "/**"):
+			expr.WriteString("(?:/.*)?")
+			i += 2
+		case strings.HasPrefix(line[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			if end := strings.IndexByte(line[i:], ']'); end > 0 {
+				expr.WriteString(strings.Replace(line[i:i+end+1], "[!", "[^", 1))
+				i += end
+			} else {
+				expr.WriteString(`\[`)
+			}
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return gitignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// ignored reports whether the slash-separated relPath is ignored. Ignored
+// directories are skipped as a whole, so their contents need no check.
+func (g *gitignore) ignored(relPath string, isDir bool) bool {
+	ignored := false
+	for _, rule := range g.rules {
+		name := relPath
+		if rule.base != "" {
+			rest, ok := strings.CutPrefix(relPath, rule.base+"/")
+			if !ok {
+				continue
+			}
+			name = rest
+		}
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.re.MatchString(name) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
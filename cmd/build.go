@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Version string
+	Commit  string // VCS revision; from the Go build info when not set
+	Date    string // build or commit time; from the Go build info when not set
+
+	modified bool // built from a working tree with uncommitted changes
+}
+
+// resolve fills the commit and date missing from the linker flags, e.g. for
+// go install builds, from the VCS information embedded by the Go toolchain
+func (b BuildInfo) resolve() BuildInfo {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+
+	fromVCS := b.Commit == ""
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if fromVCS {
+				b.Commit = setting.Value
+			}
+		case "vcs.time":
+			if b.Date == "" {
+				b.Date = setting.Value
+			}
+		case "vcs.modified":
+			b.modified = fromVCS && setting.Value == "true"
+		}
+	}
+	return b
+}
+
+// String describes the build, e.g. "code-gen v1.2.0 (commit 1a2b3c4, built
+// 2026-01-02T03:04:05Z, go1.24.5 linux/amd64)"
+func (b BuildInfo) String() string {
+	details := []string{}
+	if b.Commit != "" {
+		commit := b.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		if b.modified {
+			commit += "-dirty"
+		}
+		details = append(details, "commit "+commit)
+	}
+	if b.Date != "" {
+		details = append(details, "built "+b.Date)
+	}
+	details = append(details, fmt.Sprintf("%s %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH))
+
+	return fmt.Sprintf("code-gen %s (%s)", b.Version, strings.Join(details, ", "))
+}
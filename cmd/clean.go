@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// cleanOptions holds the flags of the clean command
+type cleanOptions struct {
+	dryRun bool
+	force  bool
+	yes    bool
+}
+
+// newCleanCommand creates the clean command
+func (a *app) newCleanCommand() *cobra.Command {
+	opts := &cleanOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "clean [project-dir]",
+		Short: "Remove the files code-gen generated",
+		Long: `Remove the files recorded in the manifest of the output directory, and the
+directories left empty, leaving files you wrote alone. Generated files edited
+since they were generated are kept unless --force is given.`,
+		Example: `  code-gen clean             # Remove generated files of the current project
+  code-gen clean --dry-run   # List what would be removed`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runClean(cmd, args, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "list the files that would be removed without removing them")
+	flags.BoolVarP(&opts.force, "force", "f", false, "also remove generated files edited since they were generated")
+	flags.BoolVarP(&opts.yes, "yes", "y", false, "remove files without asking for confirmation")
+
+	return cmd
+}
+
+// runClean removes the generated files tracked by the manifest
+func (a *app) runClean(cmd *cobra.Command, args []string, opts *cleanOptions) error {
+	logger := a.logger
+
+	workDir, err := projectDir(args)
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+	outDir := a.resolveOutputDir(cmd, workDir)
+
+	if !opts.dryRun {
+		release, err := acquireLock(outDir)
+		if err != nil {
+			if errors.Is(err, errLocked) {
+				return withKind(kindLocked, err)
+			}
+			return withKind(kindIO, err)
+		}
+		defer release()
+	}
+
+	m, err := loadManifest(outDir)
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+	if len(m.Files) == 0 {
+		logger.Info("No generated files recorded in %s", filepath.Join(outDir, manifestFile))
+		return nil
+	}
+
+	filenames := make([]string, 0, len(m.Files))
+	for filename := range m.Files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	var remove, kept []string
+	for _, filename := range filenames {
+		path := filepath.Join(outDir, filepath.FromSlash(filename))
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			delete(m.Files, filename)
+			continue
+		}
+		if !opts.force && !m.unmodified(outDir, filename) {
+			logger.Warning("Edited since generated, keeping: %s", filepath.FromSlash(filename))
+			kept = append(kept, filename)
+			continue
+		}
+		remove = append(remove, filename)
+	}
+
+	if len(remove) == 0 {
+		logger.Info("No generated files to remove")
+		return nil
+	}
+	for _, filename := range remove {
+		fmt.Println(filepath.FromSlash(filename))
+	}
+	if opts.dryRun {
+		logger.Info("Dry run - no files removed")
+		return nil
+	}
+
+	if !opts.yes && isTerminal(os.Stdin) {
+		p := a.prompts()
+		ok, err := confirm(os.Stdin, os.Stdout, fmt.Sprintf("Remove %d generated files?", len(remove)), p)
+		if err != nil {
+			return withKind(kindIO, fmt.Errorf("failed to read confirmation: %w", err))
+		}
+		if !ok {
+			logger.Warning("Clean cancelled, no files removed")
+			return nil
+		}
+	}
+
+	for _, filename := range remove {
+		path := filepath.Join(outDir, filepath.FromSlash(filename))
+		if err := os.Remove(path); err != nil {
+			return withKind(kindIO, fmt.Errorf("failed to remove %s: %w", filename, err))
+		}
+		delete(m.Files, filename)
+		removeEmptyParents(filepath.Dir(path), outDir)
+	}
+
+	// Keep tracking the edited files, so clean --force can still remove them
+	if len(m.Files) == 0 {
+		if err := os.Remove(filepath.Join(outDir, manifestFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return withKind(kindIO, fmt.Errorf("failed to remove %s: %w", manifestFile, err))
+		}
+	} else {
+		m.Inputs = ""
+		if err := m.save(outDir); err != nil {
+			return withKind(kindIO, fmt.Errorf("failed to write %s: %w", manifestFile, err))
+		}
+	}
+
+	logger.Success("Removed %d generated files", len(remove))
+	if len(kept) > 0 {
+		logger.Info("Kept %d edited files, use --force to remove them too", len(kept))
+	}
+	return nil
+}
+
+// removeEmptyParents removes dir and its parents up to, but excluding, root
+// while they are empty
+func removeEmptyParents(dir, root string) {
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root && len(dir) > len(root); dir = filepath.Dir(dir) {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return
+		}
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
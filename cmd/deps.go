@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/navyarakshakarya/code-gen/generator"
+	"github.com/navyarakshakarya/code-gen/logger"
+)
+
+// offlineEnv keeps go commands run by hooks and checks from reaching the
+// network or downloading toolchains
+var offlineEnv = []string{"GOPROXY=off", "GOTOOLCHAIN=local"}
+
+// networkCommands are the go commands hooks skip in offline mode
+var networkCommands = []string{"go mod tidy", "go mod download", "go get", "go install"}
+
+// module is a module generated code depends on
+type module struct {
+	path     string
+	version  string // empty when neither go.mod nor the pins know it
+	required bool   // required by the project's go.mod
+}
+
+// newDepsCommand creates the deps command
+func (a *app) newDepsCommand() *cobra.Command {
+	opts := &generateOptions{}
+	var missing bool
+
+	cmd := &cobra.Command{
+		Use:   "deps [project-dir]",
+		Short: "List the modules generated code depends on",
+		Long: `Render the code generate would write, without writing it, and list the
+modules it imports as module@version, one per line. Versions come from the
+project's go.mod, or from the versions code-gen pins.
+
+Run it on a machine with network access to prepare air-gapped environments:
+the list can be passed to "go mod download", or added to go.mod before
+vendoring, so "generate --offline" finds everything locally.`,
+		Example: `  code-gen deps                              # All modules generated code imports
+  code-gen deps --missing                    # Only those go.mod does not require yet
+  code-gen deps --missing | xargs go get     # Add them on a connected machine`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			modules, err := a.generatedModules(cmd, args, opts)
+			if err != nil {
+				return err
+			}
+			for _, mod := range modules {
+				if missing && mod.required {
+					continue
+				}
+				if mod.version == "" {
+					a.logger.Warning("No version known for %s", mod.path)
+					fmt.Println(mod.path)
+					continue
+				}
+				fmt.Printf("%s@%s\n", mod.path, mod.version)
+			}
+			return nil
+		},
+	}
+
+	opts.addFlags(cmd)
+	cmd.Flags().BoolVar(&missing, "missing", false, "only list modules the project's go.mod does not require")
+
+	return cmd
+}
+
+// generatedModules renders the generated files in memory and resolves the
+// modules they import
+func (a *app) generatedModules(cmd *cobra.Command, args []string, opts *generateOptions) ([]module, error) {
+	// Rendering only: no hooks, locks or writes
+	opts.dryRun = true
+
+	g, err := a.prepare(cmd, args, opts)
+	if err != nil || g.projectInfo == nil {
+		return nil, err
+	}
+	results, err := g.generator.Generate(g.projectInfo)
+	if err != nil {
+		return nil, withKind(kindTemplate, fmt.Errorf("code generation failed: %w", err))
+	}
+
+	return importedModules(results, g.workDir, g.projectInfo.ModuleName)
+}
+
+// importedModules resolves the modules the generated files import, other than
+// the standard library and the project module in workDir
+func importedModules(results []*generator.GeneratedFile, workDir, moduleName string) ([]module, error) {
+	required, err := requiredModules(workDir)
+	if err != nil {
+		return nil, withKind(kindIO, err)
+	}
+
+	modules := make(map[string]module)
+	fset := token.NewFileSet()
+	for _, result := range results {
+		file, err := parser.ParseFile(fset, result.Filename, result.Content, parser.ImportsOnly)
+		if err != nil {
+			return nil, withKind(kindTemplate, fmt.Errorf("failed to parse generated %s: %w", result.Filename, err))
+		}
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			if standardLibrary(importPath) || within(importPath, moduleName) {
+				continue
+			}
+			mod := resolveModule(importPath, required)
+			modules[mod.path] = mod
+		}
+	}
+
+	var list []module
+	for _, mod := range modules {
+		list = append(list, mod)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].path < list[j].path })
+	return list, nil
+}
+
+// resolveModule returns the module providing importPath, preferring the
+// project's requirements over the pinned versions
+func resolveModule(importPath string, required map[string]string) module {
+	if path, version := longestModule(importPath, required); path != "" {
+		return module{path: path, version: version, required: true}
+	}
+	if path, version := longestModule(importPath, generator.ModulePins); path != "" {
+		return module{path: path, version: version}
+	}
+	return module{path: importPath}
+}
+
+// longestModule returns the longest module path in modules containing importPath
+func longestModule(importPath string, modules map[string]string) (string, string) {
+	var found string
+	for path := range modules {
+		if within(importPath, path) && len(path) > len(found) {
+			found = path
+		}
+	}
+	if found == "" {
+		return "", ""
+	}
+	return found, modules[found]
+}
+
+// within reports whether importPath is modulePath or one of its packages
+func within(importPath, modulePath string) bool {
+	return importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")
+}
+
+// standardLibrary reports whether importPath belongs to the standard library,
+// whose first path element has no dot
+func standardLibrary(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// requiredModules returns the requirements of the go.mod in dir: module path -> version
+func requiredModules(dir string) (map[string]string, error) {
+	content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+
+	required := make(map[string]string)
+	inBlock := false
+	for _, line := range strings.Split(string(content), "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inBlock && fields[0] == ")":
+			inBlock = false
+		case inBlock && len(fields) >= 2:
+			required[fields[0]] = fields[1]
+		case fields[0] == "require" && len(fields) >= 2 && fields[1] == "(":
+			inBlock = true
+		case fields[0] == "require" && len(fields) >= 3:
+			required[fields[1]] = fields[2]
+		}
+	}
+	return required, nil
+}
+
+// checkOfflineModules warns about modules generated code imports that cannot
+// be resolved without network access: missing from go.mod, or neither vendored
+// nor in the module cache
+func checkOfflineModules(modules []module, workDir string, logger *logger.Logger) {
+	_, err := os.Stat(filepath.Join(workDir, "vendor", "modules.txt"))
+	vendored := err == nil
+
+	for _, mod := range modules {
+		switch {
+		case !mod.required && mod.version != "":
+			logger.Warning("go.mod does not require %s; add %s@%s (see code-gen deps --missing)", mod.path, mod.path, mod.version)
+		case !mod.required:
+			logger.Warning("go.mod does not require %s", mod.path)
+		case !vendored && !cachedModule(mod.path, mod.version):
+			logger.Warning("%s@%s is not in the module cache; run go mod download with network access", mod.path, mod.version)
+		}
+	}
+}
+
+// cachedModule reports whether the module cache holds the given module version
+func cachedModule(path, version string) bool {
+	cache := os.Getenv("GOMODCACHE")
+	if cache == "" {
+		gopath := os.Getenv("GOPATH")
+		if gopath == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return false
+			}
+			gopath = filepath.Join(home, "go")
+		}
+		cache = filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+	}
+
+	zip := filepath.Join(cache, "cache", "download", filepath.FromSlash(escapeModulePath(path)), "@v", version+".zip")
+	_, err := os.Stat(zip)
+	return err == nil
+}
+
+// escapeModulePath escapes upper-case letters as the module cache does
+// ("!" followed by the lower-case letter)
+func escapeModulePath(path string) string {
+	var escaped strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			escaped.WriteRune('!')
+			r = unicode.ToLower(r)
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// networkCommand reports whether a hook command runs a go command that
+// downloads modules
+func networkCommand(command string) bool {
+	normalized := strings.Join(strings.Fields(command), " ")
+	for _, network := range networkCommands {
+		if strings.Contains(normalized, network) {
+			return true
+		}
+	}
+	return false
+}
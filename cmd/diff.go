@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// diffContext is the number of unchanged lines around each change
+const diffContext = 3
+
+// maxDiffCells bounds the line comparisons of one diff; larger changes are
+// shown as replacing the changed region wholesale
+const maxDiffCells = 4_000_000
+
+// diffOp is one line of an edit script: ' ' kept, '-' removed, '+' added
+type diffOp struct {
+	kind byte
+	line string
+	old  int // 0-based line in the old file before this op
+	new  int // 0-based line in the new file before this op
+}
+
+// newDiffCommand creates the diff command
+func (a *app) newDiffCommand() *cobra.Command {
+	opts := &generateOptions{}
+	var exitCode bool
+
+	cmd := &cobra.Command{
+		Use:   "diff [project-dir]",
+		Short: "Show how regenerating would change the files on disk",
+		Long: `Render the code generate would write, without writing it, and print unified
+diffs against the files on disk. New files are diffed against /dev/null.
+Files edited since they were generated are diffed too, and reported, as
+generate skips them unless --force is given.`,
+		Example: `  code-gen diff                  # Diff the current project
+  code-gen diff --layout layered # Preview switching to the layered layout
+  code-gen diff --exit-code      # Fail in CI when generated code is stale`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runDiff(cmd, args, opts, exitCode)
+		},
+	}
+
+	opts.addFlags(cmd)
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "exit with code 1 when regenerating would change files")
+
+	return cmd
+}
+
+// runDiff renders the generated files and diffs them against disk
+func (a *app) runDiff(cmd *cobra.Command, args []string, opts *generateOptions, exitCode bool) error {
+	logger := a.logger
+
+	g, err := a.prepare(cmd, args, opts)
+	if err != nil {
+		return err
+	}
+	if g.projectInfo == nil {
+		return nil
+	}
+
+	m, err := loadManifest(g.outDir)
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+	results, err := g.generator.Generate(g.projectInfo)
+	if err != nil {
+		return withKind(kindTemplate, fmt.Errorf("code generation failed: %w", err))
+	}
+	applyLineEndings(results, g.separator)
+	statuses := planStatuses(results, g.outDir, g.force, m)
+
+	changed := 0
+	for _, result := range results {
+		name := filepath.ToSlash(result.Filename)
+		oldName := "a/" + name
+
+		current, err := os.ReadFile(filepath.Join(g.outDir, result.Filename))
+		if errors.Is(err, os.ErrNotExist) {
+			oldName = "/dev/null"
+		} else if err != nil {
+			return withKind(kindIO, fmt.Errorf("failed to read %s: %w", result.Filename, err))
+		}
+		if current != nil && (result.Scaffold || contentHash(string(current)) == contentHash(result.Content)) {
+			continue
+		}
+
+		changed++
+		writeDiff(os.Stdout, oldName, "b/"+name, splitLines(string(current)), splitLines(result.Content), a.useColor())
+		if statuses[result.Filename] == statusSkip {
+			logger.Warning("%s was edited since it was generated; generate skips it without --force", result.Filename)
+		}
+	}
+
+	if changed == 0 {
+		logger.Success("Generated code matches the files on disk")
+		return nil
+	}
+	logger.Info("%d of %d generated files would change", changed, len(results))
+	if exitCode {
+		return withKind(kindGeneral, fmt.Errorf("regenerating would change %d files", changed))
+	}
+	return nil
+}
+
+// splitLines splits content into lines without their line endings
+func splitLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// sameLine reports whether two lines are equal, ignoring the generation timestamp
+func sameLine(a, b string) bool {
+	if strings.HasPrefix(a, "// Generated at: ") && strings.HasPrefix(b, "// Generated at: ") {
+		return true
+	}
+	return a == b
+}
+
+// writeDiff writes the unified diff turning oldLines into newLines to w
+func writeDiff(w io.Writer, oldName, newName string, oldLines, newLines []string, color bool) {
+	paint := func(text, code string) string {
+		if !color {
+			return text
+		}
+		return code + text + colorReset
+	}
+
+	fmt.Fprintln(w, paint("--- "+oldName, colorBold))
+	fmt.Fprintln(w, paint("+++ "+newName, colorBold))
+
+	ops := editScript(oldLines, newLines)
+	for _, hunk := range hunks(ops, diffContext) {
+		first := hunk[0]
+		var oldCount, newCount int
+		for _, op := range hunk {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintln(w, paint(fmt.Sprintf("@@ -%s +%s @@", hunkRange(first.old, oldCount), hunkRange(first.new, newCount)), colorCyan))
+
+		for _, op := range hunk {
+			line := string(op.kind) + op.line
+			switch op.kind {
+			case '-':
+				line = paint(line, colorRed)
+			case '+':
+				line = paint(line, colorGreen)
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+}
+
+// hunkRange formats the start,count range of a hunk header
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// editScript returns the ops turning a into b, from the longest common
+// subsequence of their lines
+func editScript(a, b []string) []diffOp {
+	// Compare only what lies between the common prefix and suffix
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && sameLine(a[prefix], b[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && sameLine(a[len(a)-1-suffix], b[len(b)-1-suffix]) {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	var ops []diffOp
+	i, j := 0, 0
+	keep := func() {
+		ops = append(ops, diffOp{kind: ' ', line: a[i], old: i, new: j})
+		i++
+		j++
+	}
+	remove := func() {
+		ops = append(ops, diffOp{kind: '-', line: a[i], old: i, new: j})
+		i++
+	}
+	add := func() {
+		ops = append(ops, diffOp{kind: '+', line: b[j], old: i, new: j})
+		j++
+	}
+
+	for range prefix {
+		keep()
+	}
+
+	if len(midA)*len(midB) > maxDiffCells {
+		for range midA {
+			remove()
+		}
+		for range midB {
+			add()
+		}
+	} else {
+		// lcs[x][y] is the length of the longest common subsequence of
+		// midA[x:] and midB[y:]
+		lcs := make([][]int32, len(midA)+1)
+		for x := range lcs {
+			lcs[x] = make([]int32, len(midB)+1)
+		}
+		for x := len(midA) - 1; x >= 0; x-- {
+			for y := len(midB) - 1; y >= 0; y-- {
+				if sameLine(midA[x], midB[y]) {
+					lcs[x][y] = lcs[x+1][y+1] + 1
+				} else {
+					lcs[x][y] = max(lcs[x+1][y], lcs[x][y+1])
+				}
+			}
+		}
+
+		x, y := 0, 0
+		for x < len(midA) || y < len(midB) {
+			switch {
+			case x < len(midA) && y < len(midB) && sameLine(midA[x], midB[y]):
+				keep()
+				x++
+				y++
+			case y == len(midB) || x < len(midA) && lcs[x+1][y] >= lcs[x][y+1]:
+				remove()
+				x++
+			default:
+				add()
+				y++
+			}
+		}
+	}
+
+	for range suffix {
+		keep()
+	}
+	return ops
+}
+
+// hunks groups the changes of an edit script with context lines around
+// them, merging changes whose context overlaps
+func hunks(ops []diffOp, context int) [][]diffOp {
+	var result [][]diffOp
+	start, end := -1, -1
+	for k, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		from, to := max(k-context, 0), min(k+context+1, len(ops))
+		if start >= 0 && from <= end {
+			end = to
+			continue
+		}
+		if start >= 0 {
+			result = append(result, ops[start:end])
+		}
+		start, end = from, to
+	}
+	if start >= 0 {
+		result = append(result, ops[start:end])
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// opStrings renders ops as their kind followed by their line
+func opStrings(ops []diffOp) []string {
+	var lines []string
+	for _, op := range ops {
+		lines = append(lines, string(op.kind)+op.line)
+	}
+	return lines
+}
+
+func TestEditScript(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want []string
+	}{
+		{"identical", []string{"a", "b"}, []string{"a", "b"}, []string{" a", " b"}},
+		{"new file", nil, []string{"a", "b"}, []string{"+a", "+b"}},
+		{"removed file", []string{"a", "b"}, nil, []string{"-a", "-b"}},
+		{"changed line", []string{"a", "b", "c"}, []string{"a", "B", "c"}, []string{" a", "-b", "+B", " c"}},
+		{"inserted line", []string{"a", "c"}, []string{"a", "b", "c"}, []string{" a", "+b", " c"}},
+		{"removed line", []string{"a", "b", "c"}, []string{"a", "c"}, []string{" a", "-b", " c"}},
+		{"swapped lines", []string{"a", "b"}, []string{"b", "a"}, []string{"-a", " b", "+a"}},
+		{
+			"timestamps are equal",
+			[]string{"// Generated at: 2024-01-01T00:00:00Z", "a"},
+			[]string{"// Generated at: 2025-01-01T00:00:00Z", "b"},
+			[]string{" // Generated at: 2024-01-01T00:00:00Z", "-a", "+b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := opStrings(editScript(tt.a, tt.b)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("editScript(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditScriptLineNumbers(t *testing.T) {
+	ops := editScript([]string{"a", "b", "c"}, []string{"a", "B", "c"})
+
+	want := []diffOp{
+		{kind: ' ', line: "a", old: 0, new: 0},
+		{kind: '-', line: "b", old: 1, new: 1},
+		{kind: '+', line: "B", old: 2, new: 1},
+		{kind: ' ', line: "c", old: 2, new: 2},
+	}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("editScript() = %+v, want %+v", ops, want)
+	}
+}
+
+func TestHunks(t *testing.T) {
+	// Ten lines with the second and the ninth changed
+	var a, b []string
+	for i := range 10 {
+		line := fmt.Sprintf("line %d", i)
+		a = append(a, line)
+		if i == 1 || i == 8 {
+			line = strings.ToUpper(line)
+		}
+		b = append(b, line)
+	}
+	ops := editScript(a, b)
+
+	tests := []struct {
+		name    string
+		ops     []diffOp
+		context int
+		want    [][]string
+	}{
+		{"no changes", editScript(a, a), 3, nil},
+		{
+			"separate hunks", ops, 1,
+			[][]string{
+				{" line 0", "-line 1", "+LINE 1", " line 2"},
+				{" line 7", "-line 8", "+LINE 8", " line 9"},
+			},
+		},
+		{
+			"overlapping context is merged", ops, 3,
+			[][]string{opStrings(ops)},
+		},
+		{
+			"no context", ops, 0,
+			[][]string{
+				{"-line 1", "+LINE 1"},
+				{"-line 8", "+LINE 8"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]string
+			for _, hunk := range hunks(tt.ops, tt.context) {
+				got = append(got, opStrings(hunk))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hunks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
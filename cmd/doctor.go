@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/navyarakshakarya/code-gen/config"
+)
+
+// checkStatus is the outcome of a doctor check
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+// check is the result of one doctor check, with the fix for a problem and
+// the kind of failure a failed check exits with
+type check struct {
+	name   string
+	status checkStatus
+	detail string
+	fix    string
+	kind   errorKind
+}
+
+// newDoctorCommand creates the doctor command
+func (a *app) newDoctorCommand() *cobra.Command {
+	opts := &generateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "doctor [project-dir]",
+		Short: "Check the environment generated code needs",
+		Long: `Check what generating and building code for the project needs: a go.mod,
+a Go toolchain new enough for it, git, the wire tool, the modules generated
+code imports, and the configuration file. Each problem is reported with the
+command or change that fixes it.
+
+It accepts the flags of generate, so the module check matches what would be
+generated.`,
+		Example: `  code-gen doctor           # Check the current project
+  code-gen doctor --tests   # Include the modules generated tests need`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runDoctor(cmd, args, opts)
+		},
+	}
+
+	opts.addFlags(cmd)
+
+	return cmd
+}
+
+// runDoctor runs the checks and reports them
+func (a *app) runDoctor(cmd *cobra.Command, args []string, opts *generateOptions) error {
+	logger := a.logger
+
+	workDir, err := projectDir(args)
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+
+	goMod := checkGoMod(workDir)
+	checks := []check{
+		goMod,
+		checkGoToolchain(workDir),
+		checkTool("git", "needed by --git-init, --git-commit and --gitignore", "install git from https://git-scm.com"),
+		checkTool("wire", "needed to generate and check the Wire injectors", "go install github.com/google/wire/cmd/wire@latest"),
+	}
+	if goMod.status == checkOK {
+		checks = append(checks, a.checkModules(cmd, args, opts))
+	}
+	if a.configPath != "" {
+		checks = append(checks, a.checkConfig())
+	}
+
+	// The first failed check decides the exit code
+	failed := 0
+	var kind errorKind
+	for _, c := range checks {
+		message := fmt.Sprintf("%s: %s", c.name, c.detail)
+		if c.fix != "" {
+			message += "\n    fix: " + c.fix
+		}
+		switch c.status {
+		case checkOK:
+			logger.Success("%s", message)
+		case checkWarn:
+			logger.Warning("%s", message)
+		default:
+			logger.Error("%s", message)
+			if failed == 0 {
+				kind = c.kind
+			}
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return withKind(kind, fmt.Errorf("%d of %d checks failed", failed, len(checks)))
+	}
+	return nil
+}
+
+// checkGoMod checks that the project is a Go module
+func checkGoMod(workDir string) check {
+	c := check{name: "go.mod"}
+	if _, err := os.Stat(filepath.Join(workDir, "go.mod")); err != nil {
+		c.status, c.detail, c.fix, c.kind = checkFail, "not found in "+workDir, "run go mod init <module-path> in the project directory", kindConfigInvalid
+		return c
+	}
+	c.detail = "found"
+	return c
+}
+
+// checkGoToolchain checks that the local Go toolchain satisfies the go
+// directive of the project's go.mod
+func checkGoToolchain(workDir string) check {
+	c := check{name: "go"}
+	if _, err := exec.LookPath("go"); err != nil {
+		c.status, c.detail, c.fix, c.kind = checkFail, "not found in PATH", "install Go from https://go.dev/dl", kindGeneral
+		return c
+	}
+
+	// Report the installed toolchain rather than one go would switch to
+	cmd := exec.Command("go", "env", "GOVERSION")
+	cmd.Env = append(os.Environ(), "GOTOOLCHAIN=local")
+	output, err := cmd.Output()
+	if err != nil {
+		c.status, c.detail, c.fix, c.kind = checkFail, "go env failed: "+err.Error(), "check the Go installation", kindGeneral
+		return c
+	}
+	installed := strings.TrimPrefix(strings.TrimSpace(string(output)), "go")
+	c.detail = installed
+
+	required := goDirective(workDir)
+	if required == "" {
+		return c
+	}
+	c.detail = fmt.Sprintf("%s (go.mod requires %s)", installed, required)
+	if compareGoVersions(installed, required) < 0 {
+		c.status, c.kind = checkFail, kindGeneral
+		c.fix = fmt.Sprintf("install Go %s or newer, or let go download it with GOTOOLCHAIN=auto", required)
+	}
+	return c
+}
+
+// checkTool checks that a command is installed
+func checkTool(name, purpose, fix string) check {
+	c := check{name: name}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		c.status, c.detail, c.fix = checkWarn, "not found in PATH, "+purpose, fix
+		return c
+	}
+	c.detail = path
+	return c
+}
+
+// checkModules checks that go.mod requires the modules generated code imports
+func (a *app) checkModules(cmd *cobra.Command, args []string, opts *generateOptions) check {
+	c := check{name: "modules"}
+	modules, err := a.generatedModules(cmd, args, opts)
+	if err != nil {
+		c.status, c.detail, c.kind = checkFail, err.Error(), kindOf(err)
+		return c
+	}
+
+	var missing []string
+	for _, mod := range modules {
+		if !mod.required {
+			missing = append(missing, mod.path)
+		}
+	}
+	if len(missing) > 0 {
+		c.status = checkWarn
+		c.detail = "go.mod does not require " + strings.Join(missing, ", ")
+		c.fix = "code-gen deps --missing | xargs go get (with the flags passed to generate)"
+		return c
+	}
+	c.detail = fmt.Sprintf("go.mod requires all %d modules generated code imports", len(modules))
+	return c
+}
+
+// checkConfig validates the configuration file given with --config
+func (a *app) checkConfig() check {
+	c := check{name: "config"}
+	content, err := config.Read(a.configPath, a.offline)
+	if err != nil {
+		c.status, c.detail, c.kind = checkFail, err.Error(), kindIO
+		return c
+	}
+
+	issues := validateConfig(content, a.configDir())
+	if len(issues) > 0 {
+		c.status, c.kind = checkFail, kindConfigInvalid
+		c.detail = fmt.Sprintf("%s: %s", issues[0].path, issues[0].message)
+		if len(issues) > 1 {
+			c.detail += fmt.Sprintf(" (and %d more issues)", len(issues)-1)
+		}
+		c.fix = "code-gen validate " + a.configPath
+		return c
+	}
+	c.detail = a.configPath + " is valid"
+	return c
+}
+
+// goDirective returns the go version required by the go.mod in dir
+func goDirective(dir string) string {
+	content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "go" {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// compareGoVersions compares Go versions such as 1.22 and 1.24.5, ignoring
+// pre-release suffixes, returning -1, 0 or 1
+func compareGoVersions(a, b string) int {
+	parse := func(version string) []int {
+		var parts []int
+		for _, part := range strings.Split(version, ".") {
+			digits := part
+			if i := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+				digits = part[:i]
+			}
+			n, _ := strconv.Atoi(digits)
+			parts = append(parts, n)
+		}
+		return parts
+	}
+
+	pa, pb := parse(a), parse(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
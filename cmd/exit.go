@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// errorKind classifies failures so CI pipelines can branch on them
+type errorKind string
+
+const (
+	kindGeneral       errorKind = "error"
+	kindConfigInvalid errorKind = "config_invalid"
+	kindConflict      errorKind = "generation_conflict"
+	kindTemplate      errorKind = "template_error"
+	kindIO            errorKind = "io_error"
+	kindLocked        errorKind = "locked"
+	kindHook          errorKind = "hook_failed"
+)
+
+// Exit codes returned by code-gen
+const (
+	ExitGeneral       = 1
+	ExitConfigInvalid = 2
+	ExitConflict      = 3
+	ExitTemplate      = 4
+	ExitIO            = 5
+	ExitLocked        = 6
+	ExitHook          = 7
+)
+
+// exitCode returns the process exit code for the error kind
+func (k errorKind) exitCode() int {
+	switch k {
+	case kindConfigInvalid:
+		return ExitConfigInvalid
+	case kindConflict:
+		return ExitConflict
+	case kindTemplate:
+		return ExitTemplate
+	case kindIO:
+		return ExitIO
+	case kindLocked:
+		return ExitLocked
+	case kindHook:
+		return ExitHook
+	default:
+		return ExitGeneral
+	}
+}
+
+// cliError is an error tagged with its failure kind
+type cliError struct {
+	kind errorKind
+	err  error
+}
+
+func (e *cliError) Error() string {
+	return e.err.Error()
+}
+
+func (e *cliError) Unwrap() error {
+	return e.err
+}
+
+// withKind tags err with a failure kind
+func withKind(kind errorKind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &cliError{kind: kind, err: err}
+}
+
+// kindOf returns the failure kind of err, defaulting to kindGeneral
+func kindOf(err error) errorKind {
+	var cliErr *cliError
+	if errors.As(err, &cliErr) {
+		return cliErr.kind
+	}
+	return kindGeneral
+}
+
+// errorReport is the machine-readable error written to stderr
+type errorReport struct {
+	Error struct {
+		Kind     errorKind `json:"kind"`
+		ExitCode int       `json:"exit_code"`
+		Message  string    `json:"message"`
+	} `json:"error"`
+}
+
+// writeErrorReport writes err as a single JSON line to w
+func writeErrorReport(w io.Writer, err error) {
+	var report errorReport
+	report.Error.Kind = kindOf(err)
+	report.Error.ExitCode = report.Error.Kind.exitCode()
+	report.Error.Message = err.Error()
+
+	json.NewEncoder(w).Encode(report)
+}
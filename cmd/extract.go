@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/navyarakshakarya/code-gen/analyzer"
+	"github.com/navyarakshakarya/code-gen/generator"
+)
+
+// extractOptions holds the flags of the extract-interface command
+type extractOptions struct {
+	name   string
+	dryRun bool
+	force  bool
+}
+
+// newExtractCommand creates the extract-interface command
+func (a *app) newExtractCommand() *cobra.Command {
+	opts := &extractOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "extract-interface <struct> [project-dir]",
+		Short: "Generate an interface from the exported methods of a struct",
+		Long: `Generate an interface matching the exported methods of an existing struct,
+plus a compile-time assertion that the struct implements it. The file is
+written next to the struct as <struct>_interface.go and is meant to be
+edited, which helps refactoring legacy code into clean architecture.`,
+		Example: `  code-gen extract-interface userService              # Generates UserService
+  code-gen extract-interface PaymentClient --name Payer`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runExtract(args, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.name, "name", "", "interface name (default: derived from the struct name)")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "print the interface instead of writing it")
+	flags.BoolVarP(&opts.force, "force", "f", false, "overwrite an existing file")
+
+	return cmd
+}
+
+// runExtract analyzes the project and writes the extracted interface
+func (a *app) runExtract(args []string, opts *extractOptions) error {
+	logger := a.logger
+
+	separator, err := lineSeparator(a.config.LineEndings)
+	if err != nil {
+		return withKind(kindConfigInvalid, err)
+	}
+	header, err := licenseHeader(a.config, a.configDir())
+	if err != nil {
+		return withKind(kindConfigInvalid, err)
+	}
+
+	workDir, err := projectDir(args[1:])
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+
+	if err := validateGoProject(workDir); err != nil {
+		return withKind(kindConfigInvalid, fmt.Errorf("invalid Go project: %w", err))
+	}
+
+	analyzerOptions := analyzer.Options{Tags: a.config.Tags, Skip: a.config.Skip, Gitignore: a.config.Gitignore}
+	projectInfo, err := analyzer.New(logger, analyzerOptions).AnalyzeProject(workDir)
+	if err != nil {
+		return withKind(kindIO, fmt.Errorf("analysis failed: %w", err))
+	}
+
+	gen := generator.New(logger, generator.Options{Header: header})
+	file, err := gen.ExtractInterface(args[0], opts.name, projectInfo)
+	if err != nil {
+		return withKind(kindConfigInvalid, err)
+	}
+
+	if opts.dryRun {
+		fmt.Fprint(os.Stdout, file.Content)
+		return nil
+	}
+
+	// Extracted interfaces are owned by the user and not tracked in the manifest
+	files := []*generator.GeneratedFile{file}
+	applyLineEndings(files, separator)
+	_, skipped, err := writeFiles(files, workDir, planStatuses(files, workDir, opts.force, nil), logger)
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+	if skipped > 0 {
+		logger.Info("Use --force to overwrite existing files")
+		return withKind(kindConflict, fmt.Errorf("%s already exists and was not overwritten", file.Filename))
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// layers are the layer names accepted by --layers
+var layers = []string{
+	types.RepositoryLayer.String(),
+	types.UseCaseLayer.String(),
+	types.HandlerLayer.String(),
+	types.ServiceLayer.String(),
+}
+
+// interfacePattern matches interface names against a glob or, when enclosed
+// in slashes, a regular expression
+type interfacePattern struct {
+	glob string
+	re   *regexp.Regexp
+}
+
+// interfaceFilter selects the analyzed interfaces code is generated for
+type interfaceFilter struct {
+	include []interfacePattern
+	exclude []interfacePattern
+	layers  []string
+}
+
+// newInterfaceFilter compiles the include and exclude patterns and validates
+// the layer names
+func newInterfaceFilter(include, exclude, layerNames []string) (*interfaceFilter, error) {
+	f := &interfaceFilter{}
+
+	var err error
+	if f.include, err = compilePatterns(include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = compilePatterns(exclude); err != nil {
+		return nil, err
+	}
+
+	for _, layer := range layerNames {
+		layer = strings.ToLower(strings.TrimSpace(layer))
+		if !slices.Contains(layers, layer) {
+			return nil, fmt.Errorf("unknown layer %q (expected one of %s)", layer, strings.Join(layers, ", "))
+		}
+		f.layers = append(f.layers, layer)
+	}
+
+	return f, nil
+}
+
+// compilePatterns parses interface patterns
+func compilePatterns(patterns []string) ([]interfacePattern, error) {
+	var result []interfacePattern
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid interface pattern %q: %w", pattern, err)
+			}
+			result = append(result, interfacePattern{re: re})
+			continue
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid interface pattern %q: %w", pattern, err)
+		}
+		result = append(result, interfacePattern{glob: pattern})
+	}
+	return result, nil
+}
+
+// matches reports whether the pattern matches name
+func (p interfacePattern) matches(name string) bool {
+	if p.re != nil {
+		return p.re.MatchString(name)
+	}
+	ok, _ := path.Match(p.glob, name)
+	return ok
+}
+
+// selected reports whether code is generated for an interface. Patterns are
+// matched against both the interface name and package.Name.
+func (f *interfaceFilter) selected(interfaceInfo *types.InterfaceInfo) bool {
+	if len(f.layers) > 0 && !slices.Contains(f.layers, interfaceInfo.Layer.String()) {
+		return false
+	}
+
+	matches := func(patterns []interfacePattern) bool {
+		for _, p := range patterns {
+			if p.matches(interfaceInfo.Name) || p.matches(interfaceInfo.Package+"."+interfaceInfo.Name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(f.include) > 0 && !matches(f.include) {
+		return false
+	}
+	return !matches(f.exclude)
+}
+
+// apply removes the interfaces that are not selected from projectInfo,
+// returning how many were removed
+func (f *interfaceFilter) apply(projectInfo *types.ProjectInfo) int {
+	removed := 0
+	for name, interfaceInfo := range projectInfo.Interfaces {
+		if !f.selected(interfaceInfo) {
+			delete(projectInfo.Interfaces, name)
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		for _, interfaceInfo := range projectInfo.Interfaces {
+			interfaceInfo.RelatedInterfaces = slices.DeleteFunc(interfaceInfo.RelatedInterfaces, func(name string) bool {
+				_, exists := projectInfo.Interfaces[name]
+				return !exists
+			})
+		}
+	}
+	return removed
+}
@@ -0,0 +1,558 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/navyarakshakarya/code-gen/analyzer"
+	"github.com/navyarakshakarya/code-gen/generator"
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// generateOptions holds the flags of the generate command
+type generateOptions struct {
+	dryRun    bool
+	force     bool
+	yes       bool
+	gitInit   bool
+	gitCommit bool
+	bench     bool
+	tests     bool
+	layout    string
+	mode      string
+	tags      []string
+	include   []string
+	exclude   []string
+	layers    []string
+	skip      []string
+	gitignore bool
+	testFiles bool
+	offline   bool
+
+	// Exit 0 instead of with a conflict when edited files are skipped
+	allowSkipped bool
+
+	// Set by upgrade: edited files to overwrite, and keeping the other
+	// edited files is not a conflict
+	upgrade   bool
+	overwrite []string
+}
+
+// addFlags registers the generate flags on cmd
+func (o *generateOptions) addFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.BoolVar(&o.dryRun, "dry-run", false, "show what would be generated without creating files")
+	flags.BoolVarP(&o.force, "force", "f", false, "overwrite existing .gen.go files")
+	flags.BoolVarP(&o.yes, "yes", "y", false, "write files without asking for confirmation")
+	flags.BoolVar(&o.gitInit, "git-init", false, "initialize a git repository with a Go .gitignore in the output directory")
+	flags.BoolVar(&o.gitCommit, "git-commit", false, "commit the generated files to the git repository")
+	flags.BoolVar(&o.bench, "bench", false, "generate benchmark stubs for repositories and use cases")
+	flags.BoolVar(&o.tests, "tests", false, "generate table-driven tests with mocked dependencies for each implementation")
+	flags.StringVar(&o.mode, "mode", generator.ModeImplementations, "what to generate: implementations or mocks")
+	flags.StringVar(&o.layout, "layout", generator.LayoutFlat, "package layout of generated files: flat or layered")
+	flags.StringSliceVar(&o.tags, "tags", nil, "build tags to include during analysis (comma separated)")
+	flags.StringSliceVar(&o.skip, "skip", nil, "paths the analyzer skips, replacing the default list (dirs end in /, comma separated)")
+	flags.BoolVar(&o.gitignore, "gitignore", false, "skip files ignored by .gitignore during analysis")
+	flags.BoolVar(&o.testFiles, "analyze-tests", false, "analyze interfaces declared in _test.go files (with --mode mocks)")
+	flags.StringSliceVar(&o.include, "include", nil, "only generate for interfaces matching these glob or /regex/ patterns (comma separated)")
+	flags.StringSliceVar(&o.exclude, "exclude", nil, "skip interfaces matching these glob or /regex/ patterns (comma separated)")
+	flags.BoolVar(&o.offline, "offline", false, "work without network access: skip hooks running go get or go mod tidy and report modules missing locally")
+	flags.StringSliceVar(&o.layers, "layers", nil, "only generate for interfaces of these layers: repository, usecase, handler, service")
+}
+
+// addAllowSkippedFlag registers --allow-skipped on the commands writing files
+// like generate
+func (o *generateOptions) addAllowSkippedFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.allowSkipped, "allow-skipped", false, "exit 0 instead of with exit code 3 when files edited by hand are skipped")
+}
+
+// newGenerateCommand creates the generate command
+func (a *app) newGenerateCommand() *cobra.Command {
+	opts := &generateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "generate [project-dir]",
+		Short: "Analyze a Go project and generate implementations",
+		Long: `Analyze the Go project in project-dir (default: current directory) and
+generate implementations, a factory and Wire providers for its interfaces.`,
+		Example: `  code-gen generate                      # Generate code for current project
+  code-gen generate ./service --force    # Overwrite existing files
+  code-gen generate --dry-run            # Preview what would be generated
+  code-gen generate --yes                # Skip the confirmation prompt
+  code-gen generate --mode mocks         # Generate testify mocks
+  code-gen generate --include 'User*' --exclude '/Legacy/'
+  code-gen generate --layers repository,usecase
+  code-gen generate -o ./out --git-init --git-commit
+  code-gen generate --tags integration,dev`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runGenerate(cmd, args, opts)
+		},
+	}
+
+	opts.addFlags(cmd)
+	opts.addAllowSkippedFlag(cmd)
+
+	return cmd
+}
+
+// generation is an analyzed project ready to be rendered
+type generation struct {
+	workDir     string
+	outDir      string
+	mode        string
+	force       bool
+	offline     bool // keep hooks and checks off the network
+	separator   string
+	options     generator.Options
+	generator   *generator.Generator
+	projectInfo *types.ProjectInfo // nil when there is nothing to generate
+}
+
+// prepare resolves the options of cmd, analyzes the project and validates the
+// names of the code it would generate
+func (a *app) prepare(cmd *cobra.Command, args []string, opts *generateOptions) (*generation, error) {
+	logger := a.logger
+
+	// Flags take precedence over the configuration file
+	force := a.config.Force
+	if cmd.Flags().Changed("force") {
+		force = opts.force
+	}
+	tags := a.config.Tags
+	if cmd.Flags().Changed("tags") {
+		tags = opts.tags
+	}
+	offline := a.config.Offline
+	if cmd.Flags().Changed("offline") {
+		offline = opts.offline
+	}
+	tests := a.config.Tests
+	if cmd.Flags().Changed("tests") {
+		tests = opts.tests
+	}
+	bench := a.config.Bench
+	if cmd.Flags().Changed("bench") {
+		bench = opts.bench
+	}
+	layout := opts.layout
+	if !cmd.Flags().Changed("layout") && a.config.Layout != "" {
+		layout = a.config.Layout
+	}
+	if layout != generator.LayoutFlat && layout != generator.LayoutLayered {
+		return nil, withKind(kindConfigInvalid, fmt.Errorf("unknown layout %q (expected %s or %s)", layout, generator.LayoutFlat, generator.LayoutLayered))
+	}
+	mode := opts.mode
+	if !cmd.Flags().Changed("mode") && a.config.Mode != "" {
+		mode = a.config.Mode
+	}
+	if mode != generator.ModeImplementations && mode != generator.ModeMocks {
+		return nil, withKind(kindConfigInvalid, fmt.Errorf("unknown mode %q (expected %s or %s)", mode, generator.ModeImplementations, generator.ModeMocks))
+	}
+
+	include := a.config.Include
+	if cmd.Flags().Changed("include") {
+		include = opts.include
+	}
+	exclude := a.config.Exclude
+	if cmd.Flags().Changed("exclude") {
+		exclude = opts.exclude
+	}
+	layerNames := a.config.Layers
+	if cmd.Flags().Changed("layers") {
+		layerNames = opts.layers
+	}
+	filter, err := newInterfaceFilter(include, exclude, layerNames)
+	if err != nil {
+		return nil, withKind(kindConfigInvalid, err)
+	}
+
+	style := generator.Style{
+		Receiver:       a.config.Style.Receiver,
+		Constructor:    a.config.Style.Constructor,
+		ValueReceivers: a.config.Style.ValueReceivers,
+		ErrorWrapping:  a.config.Style.ErrorWrapping,
+		Comments:       a.config.Style.Comments,
+	}
+	if err := style.Validate(); err != nil {
+		return nil, withKind(kindConfigInvalid, err)
+	}
+
+	separator, err := lineSeparator(a.config.LineEndings)
+	if err != nil {
+		return nil, withKind(kindConfigInvalid, err)
+	}
+	header, err := licenseHeader(a.config, a.configDir())
+	if err != nil {
+		return nil, withKind(kindConfigInvalid, err)
+	}
+
+	// Resolve project directory
+	workDir, err := projectDir(args)
+	if err != nil {
+		return nil, withKind(kindIO, err)
+	}
+
+	// Validate Go project
+	if err := validateGoProject(workDir); err != nil {
+		return nil, withKind(kindConfigInvalid, fmt.Errorf("invalid Go project: %w", err))
+	}
+
+	// Determine output directory
+	outDir := a.resolveOutputDir(cmd, workDir)
+
+	// Pre-generate hooks may produce code the analysis depends on
+	if len(a.config.Hooks.PreGenerate) > 0 && !opts.dryRun {
+		if err := os.MkdirAll(outDir, 0755); err != nil {
+			return nil, withKind(kindIO, fmt.Errorf("failed to create output directory: %w", err))
+		}
+		if err := runHooks("pre_generate", a.config.Hooks.PreGenerate, outDir, offline, logger); err != nil {
+			return nil, withKind(kindHook, err)
+		}
+	}
+
+	logger.Info("Analyzing Go project in: %s", workDir)
+
+	// Initialize analyzer with build tags and skipped paths
+	analyzerOptions := analyzer.Options{Tags: tags, Skip: a.config.Skip, Gitignore: a.config.Gitignore, Tests: a.config.AnalyzeTests}
+	if cmd.Flags().Changed("skip") {
+		analyzerOptions.Skip = opts.skip
+	}
+	if cmd.Flags().Changed("gitignore") {
+		analyzerOptions.Gitignore = opts.gitignore
+	}
+	if cmd.Flags().Changed("analyze-tests") {
+		analyzerOptions.Tests = opts.testFiles
+	}
+	if analyzerOptions.Tests && mode != generator.ModeMocks {
+		logger.Warning("Test files are only analyzed with --mode %s", generator.ModeMocks)
+		analyzerOptions.Tests = false
+	}
+	analyzer := analyzer.New(logger, analyzerOptions)
+
+	// Analyze project
+	analysisStart := time.Now()
+	projectInfo, err := analyzer.AnalyzeProject(workDir)
+	a.stats.phase("analysis", analysisStart)
+	if err != nil {
+		return nil, withKind(kindIO, fmt.Errorf("analysis failed: %w", err))
+	}
+
+	if removed := filter.apply(projectInfo); removed > 0 {
+		logger.Info("Filtered out %d interfaces", removed)
+		if len(projectInfo.Interfaces) == 0 {
+			logger.Warning("No interfaces match the include, exclude and layer filters")
+			return &generation{outDir: outDir}, nil
+		}
+	}
+
+	if len(projectInfo.Interfaces) == 0 {
+		logger.Warning("No interfaces found in project")
+		logger.Info("Make sure your interfaces follow naming conventions (e.g., *Repo, *UseCase, *Handler)")
+		return &generation{outDir: outDir}, nil
+	}
+
+	logger.Success("Analysis complete: found %d interfaces, %d structs",
+		len(projectInfo.Interfaces), len(projectInfo.Structs))
+	a.stats.project(workDir, len(projectInfo.Interfaces))
+
+	// Initialize generator
+	options := generator.Options{
+		Mode:           mode,
+		Benchmarks:     bench,
+		Tests:          tests,
+		Layout:         layout,
+		LayerDirs:      a.config.LayerDirs,
+		BaseImportPath: outputImportPath(workDir, outDir, projectInfo.ModuleName),
+		Header:         header,
+		Style:          style,
+	}
+	gen := generator.New(logger, options)
+
+	// Reject names that would produce uncompilable code
+	if err := gen.Validate(projectInfo); err != nil {
+		return nil, withKind(kindConfigInvalid, err)
+	}
+
+	return &generation{
+		workDir:     workDir,
+		outDir:      outDir,
+		mode:        mode,
+		force:       force,
+		offline:     offline,
+		separator:   separator,
+		options:     options,
+		generator:   gen,
+		projectInfo: projectInfo,
+	}, nil
+}
+
+// runGenerate analyzes the project and writes the generated files
+func (a *app) runGenerate(cmd *cobra.Command, args []string, opts *generateOptions) error {
+	logger := a.logger
+
+	g, err := a.prepare(cmd, args, opts)
+	if err != nil {
+		return err
+	}
+	if g.projectInfo == nil {
+		return nil
+	}
+	outDir, mode, force, projectInfo := g.outDir, g.mode, g.force, g.projectInfo
+
+	// Keep concurrent runs from interleaving writes into the same directory
+	if !opts.dryRun {
+		release, err := acquireLock(outDir)
+		if err != nil {
+			if errors.Is(err, errLocked) {
+				return withKind(kindLocked, err)
+			}
+			return withKind(kindIO, err)
+		}
+		defer release()
+	}
+
+	// Skip generation entirely when nothing it depends on has changed
+	m, err := loadManifest(outDir)
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+	inputs, err := inputsHash(a.version, g.options, g.separator, projectInfo)
+	if err != nil {
+		return withKind(kindTemplate, err)
+	}
+	if opts.upgrade {
+		if len(m.Files) == 0 {
+			return withKind(kindConfigInvalid, fmt.Errorf("no generated files are recorded in %s; run code-gen generate first", filepath.Join(outDir, manifestFile)))
+		}
+		logger.Info("Upgrading code generated by code-gen %s to %s", m.Version, a.version)
+	}
+	if !force && m.upToDate(outDir, inputs, g.separator) {
+		logger.Success("Generated code is up to date")
+		return nil
+	}
+
+	// Generate code
+	generationStart := time.Now()
+	results, err := g.generator.Generate(projectInfo)
+	a.stats.phase("generation", generationStart)
+	if err != nil {
+		return withKind(kindTemplate, fmt.Errorf("code generation failed: %w", err))
+	}
+	applyLineEndings(results, g.separator)
+
+	// Preview the planned file tree
+	statuses := planStatuses(results, outDir, force, m)
+	if err := overwriteEdited(statuses, results, opts.overwrite); err != nil {
+		return withKind(kindConfigInvalid, err)
+	}
+	if opts.dryRun || !opts.yes {
+		printTree(os.Stdout, outDir, results, statuses, a.useColor(), a.plain)
+	}
+
+	if opts.dryRun {
+		logger.Info("Dry run - no files written")
+		return nil
+	}
+
+	// Ask before writing when running interactively
+	if !opts.yes && isTerminal(os.Stdin) {
+		p := a.prompts()
+		ok, err := confirm(os.Stdin, os.Stdout, p.confirmWrite, p)
+		if err != nil {
+			return withKind(kindIO, fmt.Errorf("failed to read confirmation: %w", err))
+		}
+		if !ok {
+			logger.Warning("Generation cancelled, no files written")
+			return nil
+		}
+	}
+
+	var created []string
+	if opts.gitInit {
+		created, err = gitInit(outDir)
+		if err != nil {
+			return withKind(kindIO, fmt.Errorf("failed to initialize git repository: %w", err))
+		}
+		logger.Info("Initialized git repository in: %s", outDir)
+	}
+
+	writeStart := time.Now()
+	written, skipped, err := writeFiles(results, outDir, statuses, logger)
+	a.stats.phase("write", writeStart)
+	if err != nil {
+		return withKind(kindIO, fmt.Errorf("%w (no files were changed)", err))
+	}
+
+	// Record what was generated; the inputs only count as generated once no
+	// file was skipped
+	upToDate := 0
+	hashes := make(map[string]string)
+	var scaffolds []string
+	for _, result := range results {
+		if result.Scaffold {
+			// Scaffolds belong to the project once written
+			scaffolds = append(scaffolds, result.Filename)
+			continue
+		}
+		if statuses[result.Filename] == statusUpToDate {
+			upToDate++
+		} else if !slices.Contains(written, result.Filename) {
+			continue
+		}
+		hashes[result.Filename] = contentHash(result.Content)
+	}
+	m.record(a.version, inputs, hashes, skipped == 0)
+	m.recordScaffolds(scaffolds)
+	a.stats.files(len(results), len(written), upToDate, skipped)
+	if err := m.save(outDir); err != nil {
+		return withKind(kindIO, fmt.Errorf("failed to write %s: %w", manifestFile, err))
+	}
+
+	logger.Success("Code generation complete!")
+	logger.Info("Generated %d files, %d up to date, skipped %d existing files", len(written), upToDate, skipped)
+
+	// Report what generated code needs that is not available locally
+	if g.offline {
+		modules, err := importedModules(results, g.workDir, projectInfo.ModuleName)
+		if err != nil {
+			return err
+		}
+		checkOfflineModules(modules, g.workDir, logger)
+	}
+
+	if len(written) > 0 {
+		hooksStart := time.Now()
+		err := runHooks("post_generate", a.config.Hooks.PostGenerate, outDir, g.offline, logger)
+		a.stats.phase("hooks", hooksStart)
+		if err != nil {
+			return withKind(kindHook, err)
+		}
+	}
+
+	// Validate the injectors once hooks had a chance to add dependencies
+	if len(written) > 0 && mode == generator.ModeImplementations {
+		checkWire(results, outDir, g.offline, logger)
+	}
+
+	if opts.gitCommit {
+		message := fmt.Sprintf("Generate clean architecture code with code-gen %s", a.version)
+		committed, err := gitCommit(outDir, append(append(created, written...), manifestFile), message)
+		if err != nil {
+			return withKind(kindIO, fmt.Errorf("failed to commit generated files: %w", err))
+		}
+		if committed {
+			logger.Success("Committed generated files: %s", message)
+		} else {
+			logger.Info("Generated files unchanged, nothing to commit")
+		}
+	}
+
+	if opts.upgrade {
+		reportUpgrade(results, outDir, statuses, m, logger)
+		return nil
+	}
+
+	if skipped > 0 {
+		logger.Info("Use --force to overwrite existing files")
+		if !opts.allowSkipped {
+			return withKind(kindConflict, fmt.Errorf("%d generated files already exist and were not overwritten", skipped))
+		}
+	}
+
+	logger.Info("\nNext steps:")
+	logger.Info("  1. Review generated code")
+	logger.Info("  2. Implement TODO methods")
+	if g.offline {
+		logger.Info("  3. Run: go mod tidy (once the modules listed by code-gen deps are available)")
+	} else {
+		logger.Info("  3. Run: go mod tidy")
+	}
+	logger.Info("  4. Run: go generate ./... to generate the Wire injectors")
+	logger.Info("  5. Run: go build")
+
+	return nil
+}
+
+// resolveOutputDir returns the output directory from flags, config or the project directory
+func (a *app) resolveOutputDir(cmd *cobra.Command, workDir string) string {
+	if cmd.Flags().Changed("output") {
+		return a.outputDir
+	}
+	if a.config.Output != "" {
+		return a.config.Output
+	}
+	return workDir
+}
+
+// outputImportPath returns the import path of outDir within the module rooted at
+// workDir, or the module path when outDir is outside of it
+func outputImportPath(workDir, outDir, moduleName string) string {
+	absOut, err := filepath.Abs(outDir)
+	if err != nil {
+		return moduleName
+	}
+
+	rel, err := filepath.Rel(workDir, absOut)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return moduleName
+	}
+
+	return path.Join(moduleName, filepath.ToSlash(rel))
+}
+
+// projectDir returns the absolute project directory from args or the current directory
+func projectDir(args []string) (string, error) {
+	if len(args) == 0 {
+		dir, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+		return dir, nil
+	}
+
+	dir, err := filepath.Abs(args[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	return dir, nil
+}
+
+func validateGoProject(dir string) error {
+	// Check for go.mod
+	goModPath := filepath.Join(dir, "go.mod")
+	if _, err := os.Stat(goModPath); err != nil {
+		return fmt.Errorf("go.mod not found - not a Go module")
+	}
+
+	// Check for .go files
+	hasGoFiles := false
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if filepath.Ext(path) == ".go" && !info.IsDir() {
+			hasGoFiles = true
+			return filepath.SkipDir // Found at least one, can stop
+		}
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if !hasGoFiles {
+		return fmt.Errorf("no Go source files found")
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// goGitignore is written when code-gen initializes a new repository
+const goGitignore = `# Binaries
+bin/
+dist/
+*.exe
+*.dll
+*.so
+*.dylib
+
+# Test and coverage output
+*.test
+*.out
+coverage.html
+
+# Dependencies
+vendor/
+
+# Environment
+.env
+.env.*
+
+# code-gen temporary files
+.code-gen.lock
+*.code-gen-tmp
+*.code-gen-bak
+
+# Editors and OS files
+.idea/
+.vscode/
+*.swp
+.DS_Store
+`
+
+// runGit runs git with args in dir and returns its trimmed output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(out.String()))
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// isGitRepo reports whether dir is inside a git work tree
+func isGitRepo(dir string) bool {
+	out, err := runGit(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// gitInit initializes a repository with a Go .gitignore in dir unless it is
+// already inside one, and returns the files it created
+func gitInit(dir string) ([]string, error) {
+	if isGitRepo(dir) {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if _, err := runGit(dir, "init"); err != nil {
+		return nil, err
+	}
+
+	var created []string
+	gitignorePath := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(gitignorePath); os.IsNotExist(err) {
+		if err := os.WriteFile(gitignorePath, []byte(goGitignore), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write .gitignore: %w", err)
+		}
+		created = append(created, ".gitignore")
+	}
+
+	return created, nil
+}
+
+// gitCommit stages files (relative to dir) and commits them with message.
+// It reports false when the files have no changes to commit.
+func gitCommit(dir string, files []string, message string) (bool, error) {
+	if len(files) == 0 {
+		return false, nil
+	}
+
+	if _, err := runGit(dir, append([]string{"add", "--"}, files...)...); err != nil {
+		return false, err
+	}
+
+	// Nothing staged means the generated files are unchanged
+	if _, err := runGit(dir, append([]string{"diff", "--cached", "--quiet", "--"}, files...)...); err == nil {
+		return false, nil
+	}
+
+	if _, err := runGit(dir, append([]string{"commit", "-m", message, "--"}, files...)...); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/navyarakshakarya/code-gen/logger"
+)
+
+// runHooks runs the commands of a hook stage in dir through the system shell,
+// stopping at the first failure. Offline, commands downloading modules are
+// skipped and go commands may not reach the network.
+func runHooks(stage string, commands []string, dir string, offline bool, logger *logger.Logger) error {
+	for _, command := range commands {
+		if offline && networkCommand(command) {
+			logger.Warning("Skipping %s hook in offline mode: %s", stage, command)
+			continue
+		}
+		logger.Info("Running %s hook: %s", stage, command)
+
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/C", command)
+		} else {
+			cmd = exec.Command("sh", "-c", command)
+		}
+		cmd.Dir = dir
+		if offline {
+			cmd.Env = append(os.Environ(), offlineEnv...)
+		}
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", stage, command, err)
+		}
+		logger.Success("Hook completed: %s", command)
+	}
+	return nil
+}
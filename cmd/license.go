@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/navyarakshakarya/code-gen/config"
+)
+
+// licenseHeader builds the comment block prepended to generated files from
+// the license and copyright options. A license naming an existing file,
+// relative to baseDir, is used as a custom header; anything else is taken as
+// an SPDX identifier.
+func licenseHeader(cfg *config.Config, baseDir string) (string, error) {
+	if cfg.License == "" {
+		if cfg.Copyright != "" {
+			return fmt.Sprintf("// Copyright %d %s\n", time.Now().Year(), cfg.Copyright), nil
+		}
+		return "", nil
+	}
+
+	path := cfg.License
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read license header %s: %w", cfg.License, err)
+		}
+		return commentBlock(string(data)), nil
+	}
+
+	if strings.ContainsAny(cfg.License, " /\\") {
+		return "", fmt.Errorf("license %q is neither an SPDX identifier nor an existing file", cfg.License)
+	}
+
+	var header strings.Builder
+	if cfg.Copyright != "" {
+		header.WriteString(fmt.Sprintf("// Copyright %d %s\n", time.Now().Year(), cfg.Copyright))
+	}
+	header.WriteString(fmt.Sprintf("// SPDX-License-Identifier: %s\n", cfg.License))
+	return header.String(), nil
+}
+
+// commentBlock turns text into line comments, keeping lines that already are comments
+func commentBlock(text string) string {
+	var block strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n")), "\n") {
+		line = strings.TrimRight(line, " \t")
+		switch {
+		case strings.HasPrefix(line, "//"):
+			block.WriteString(line + "\n")
+		case line == "":
+			block.WriteString("//\n")
+		default:
+			block.WriteString("// " + line + "\n")
+		}
+	}
+	return block.String()
+}
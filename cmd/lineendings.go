@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/navyarakshakarya/code-gen/generator"
+)
+
+// Line ending styles accepted in the configuration file
+const (
+	lineEndingsLF     = "lf"
+	lineEndingsCRLF   = "crlf"
+	lineEndingsNative = "native"
+)
+
+// lineSeparator returns the line separator for a line ending style
+func lineSeparator(style string) (string, error) {
+	switch style {
+	case "", lineEndingsLF:
+		return "\n", nil
+	case lineEndingsCRLF:
+		return "\r\n", nil
+	case lineEndingsNative:
+		if runtime.GOOS == "windows" {
+			return "\r\n", nil
+		}
+		return "\n", nil
+	default:
+		return "", fmt.Errorf("unknown line_endings %q (expected %s, %s or %s)", style, lineEndingsLF, lineEndingsCRLF, lineEndingsNative)
+	}
+}
+
+// applyLineEndings converts the content of generated files to use separator
+func applyLineEndings(results []*generator.GeneratedFile, separator string) {
+	if separator == "\n" {
+		return
+	}
+	for _, result := range results {
+		result.Content = strings.ReplaceAll(result.Content, "\n", separator)
+	}
+}
+
+// hasLineEndings reports whether every line of content ends with separator
+func hasLineEndings(content, separator string) bool {
+	crlf := strings.Count(content, "\r\n")
+	if separator == "\r\n" {
+		return crlf == strings.Count(content, "\n")
+	}
+	return crlf == 0
+}
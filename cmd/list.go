@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/navyarakshakarya/code-gen/generator"
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// listSections are the layers list prints, in order, with their headings
+var listSections = []struct {
+	layer   types.LayerType
+	heading string
+}{
+	{types.RepositoryLayer, "Repositories"},
+	{types.ServiceLayer, "Services"},
+	{types.UseCaseLayer, "Use cases"},
+	{types.HandlerLayer, "Handlers"},
+}
+
+// newListCommand creates the list command
+func (a *app) newListCommand() *cobra.Command {
+	opts := &generateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "list [project-dir]",
+		Short: "Summarize the interfaces code-gen would implement",
+		Long: `Analyze the project and print a tree of its repositories, services, use
+cases and handlers: the implementation file generated for each, the entity
+and table or collection repositories store, and the dependencies of the
+generated constructors. Nothing is generated or written.`,
+		Example: `  code-gen list                    # Summarize the current project
+  code-gen list --layout layered   # Show the files of the layered layout`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, err := a.prepare(cmd, args, opts)
+			if err != nil {
+				return err
+			}
+			if g.projectInfo == nil {
+				return nil
+			}
+			printComponents(os.Stdout, g.workDir, g.generator.Components(g.projectInfo), a.plain)
+			return nil
+		},
+	}
+
+	opts.addFlags(cmd)
+
+	return cmd
+}
+
+// printComponents writes the components grouped by layer as a tree
+func printComponents(w io.Writer, projectDir string, components []generator.Component, plain bool) {
+	fmt.Fprintln(w, filepath.Base(projectDir))
+
+	var sections []string
+	grouped := make(map[string][]generator.Component)
+	for _, section := range listSections {
+		for _, component := range components {
+			if component.Layer == section.layer {
+				grouped[section.heading] = append(grouped[section.heading], component)
+			}
+		}
+		if len(grouped[section.heading]) > 0 {
+			sections = append(sections, section.heading)
+		}
+	}
+
+	for i, heading := range sections {
+		branch, indent := treeBranch(i == len(sections)-1, plain)
+		fmt.Fprintf(w, "%s%s\n", branch, heading)
+
+		group := grouped[heading]
+		for j, component := range group {
+			branch, childIndent := treeBranch(j == len(group)-1, plain)
+			methods := "methods"
+			if component.Methods == 1 {
+				methods = "method"
+			}
+			fmt.Fprintf(w, "%s%s%s (%s, %d %s)\n", indent, branch, component.Interface, component.Package, component.Methods, methods)
+
+			details := componentDetails(component)
+			for k, detail := range details {
+				branch, _ := treeBranch(k == len(details)-1, plain)
+				fmt.Fprintf(w, "%s%s%s%s\n", indent, childIndent, branch, detail)
+			}
+		}
+	}
+}
+
+// componentDetails returns the lines listed under a component
+func componentDetails(component generator.Component) []string {
+	if component.Constructor != "" {
+		return []string{"handwritten: " + component.Constructor}
+	}
+
+	details := []string{"file: " + component.File}
+	if component.Entity != "" {
+		store := "table"
+		if component.Storage == "mongo" {
+			store = "collection"
+		}
+		details = append(details, fmt.Sprintf("entity: %s (%s %s %s)", component.Entity, component.Storage, store, component.Table))
+	}
+	if len(component.Dependencies) > 0 {
+		details = append(details, "needs: "+strings.Join(component.Dependencies, ", "))
+	}
+	return details
+}
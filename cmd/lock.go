@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// lockFile marks an output directory as being generated into
+const lockFile = ".code-gen.lock"
+
+// staleLockAge is the age after which a lock left behind by a crashed run is ignored
+const staleLockAge = 10 * time.Minute
+
+// errLocked reports that another code-gen process holds the lock
+var errLocked = errors.New("output directory is locked")
+
+// acquireLock creates the lock file in outputDir so concurrent runs don't
+// interleave writes. The returned function releases the lock.
+func acquireLock(outputDir string) (func(), error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	path := filepath.Join(outputDir, lockFile)
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "pid %d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create %s: %w", lockFile, err)
+		}
+
+		// Take over locks left behind by a crashed run
+		info, statErr := os.Stat(path)
+		if statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+			continue
+		}
+
+		owner, _ := os.ReadFile(path)
+		return nil, fmt.Errorf("%w by another code-gen process (%s); remove %s if no other run is active",
+			errLocked, strings.TrimSpace(string(owner)), path)
+	}
+
+	return nil, fmt.Errorf("%w: could not replace stale %s", errLocked, path)
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/navyarakshakarya/code-gen/generator"
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// manifestFile records what code-gen generated in an output directory
+const manifestFile = ".code-gen-manifest.json"
+
+// manifest tracks the inputs of the last generation and the content hash of
+// every generated file, so unchanged files are not rewritten and files edited
+// by hand can be told apart from generated ones
+type manifest struct {
+	Version   string            `json:"version"`
+	Inputs    string            `json:"inputs"`
+	Files     map[string]string `json:"files"`               // slash-separated path -> content hash
+	Scaffolds []string          `json:"scaffolds,omitempty"` // slash-separated paths of the scaffolds of the last generation
+}
+
+// loadManifest reads the manifest of outputDir, returning an empty manifest
+// when none exists yet
+func loadManifest(outputDir string) (*manifest, error) {
+	m := &manifest{Files: make(map[string]string)}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", manifestFile, err)
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestFile, err)
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]string)
+	}
+	return m, nil
+}
+
+// save writes the manifest to outputDir
+func (m *manifest) save(outputDir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputDir, manifestFile), append(data, '\n'), 0644)
+}
+
+// record stores the content hashes of generated files by filename, and the
+// inputs that produced them when the generation was complete
+func (m *manifest) record(version, inputs string, hashes map[string]string, complete bool) {
+	for filename, hash := range hashes {
+		m.Files[filepath.ToSlash(filename)] = hash
+	}
+	m.Version = version
+	m.Inputs = ""
+	if complete {
+		m.Inputs = inputs
+	}
+}
+
+// recordScaffolds stores the paths of the scaffolds of a generation, which
+// belong to the project and are only checked for existence
+func (m *manifest) recordScaffolds(filenames []string) {
+	m.Scaffolds = nil
+	for _, filename := range filenames {
+		m.Scaffolds = append(m.Scaffolds, filepath.ToSlash(filename))
+	}
+	sort.Strings(m.Scaffolds)
+}
+
+// tracked returns the recorded hash of a generated file
+func (m *manifest) tracked(filename string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	hash, ok := m.Files[filepath.ToSlash(filename)]
+	return hash, ok
+}
+
+// unmodified reports whether the file on disk still has the content recorded
+// in the manifest
+func (m *manifest) unmodified(outputDir, filename string) bool {
+	hash, ok := m.tracked(filename)
+	if !ok {
+		return false
+	}
+	diskHash, err := fileHash(filepath.Join(outputDir, filename))
+	return err == nil && diskHash == hash
+}
+
+// upToDate reports whether the last generation used the same inputs and all
+// of its files are still on disk unmodified, with separator line endings, and
+// none of its scaffolds was deleted
+func (m *manifest) upToDate(outputDir, inputs, separator string) bool {
+	if m.Inputs == "" || m.Inputs != inputs || len(m.Files) == 0 {
+		return false
+	}
+	for _, filename := range m.Scaffolds {
+		if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(filename))); err != nil {
+			return false
+		}
+	}
+	for filename := range m.Files {
+		if !m.unmodified(outputDir, filename) {
+			return false
+		}
+		data, err := os.ReadFile(filepath.Join(outputDir, filename))
+		if err != nil || !hasLineEndings(string(data), separator) {
+			return false
+		}
+	}
+	return true
+}
+
+// inputsHash hashes everything generation depends on: the tool version, the
+// generator options, the line separator and the analyzed project
+func inputsHash(version string, options generator.Options, separator string, projectInfo *types.ProjectInfo) (string, error) {
+	data, err := json.Marshal(struct {
+		Version   string
+		Options   generator.Options
+		Separator string
+		Project   *types.ProjectInfo
+	}{version, options, separator, projectInfo})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash generation inputs: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// contentHash hashes generated content, ignoring the generation timestamp and
+// line endings, so converting the line endings of a file does not count as
+// editing it
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(withoutTimestamp(strings.ReplaceAll(content, "\r\n", "\n"))))
+	return hex.EncodeToString(sum[:])
+}
+
+// withoutTimestamp drops the generation timestamp line from content, keeping
+// its line endings
+func withoutTimestamp(content string) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, "// Generated at: ") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sameBytes reports whether the file at path holds exactly content, apart
+// from the generation timestamp
+func sameBytes(path, content string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && withoutTimestamp(string(data)) == withoutTimestamp(content)
+}
+
+// fileHash returns the content hash of a file on disk
+func fileHash(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return contentHash(string(data)), nil
+}
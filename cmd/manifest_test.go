@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestContentHash(t *testing.T) {
+	base := "// Code generated by code-gen. DO NOT EDIT.\n// Generated at: 2024-01-01T00:00:00Z\n\npackage shop\n"
+
+	tests := []struct {
+		name    string
+		content string
+		same    bool
+	}{
+		{"identical", base, true},
+		{"other timestamp", "// Code generated by code-gen. DO NOT EDIT.\n// Generated at: 2025-06-30T12:00:00Z\n\npackage shop\n", true},
+		{"crlf line endings", "// Code generated by code-gen. DO NOT EDIT.\r\n// Generated at: 2024-01-01T00:00:00Z\r\n\r\npackage shop\r\n", true},
+		{"no timestamp", "// Code generated by code-gen. DO NOT EDIT.\n\npackage shop\n", true},
+		{"edited", base + "\nvar edited = true\n", false},
+		{"other package", "// Code generated by code-gen. DO NOT EDIT.\n// Generated at: 2024-01-01T00:00:00Z\n\npackage store\n", false},
+		{"missing final newline", "// Code generated by code-gen. DO NOT EDIT.\n// Generated at: 2024-01-01T00:00:00Z\n\npackage shop", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := contentHash(tt.content) == contentHash(base); same != tt.same {
+				t.Errorf("contentHash(%q) == contentHash(base) is %v, want %v", tt.content, same, tt.same)
+			}
+		})
+	}
+}
+
+func TestUpToDate(t *testing.T) {
+	const content = "// Generated at: 2024-01-01T00:00:00Z\npackage shop\n"
+	const inputs = "inputs"
+
+	tests := []struct {
+		name      string
+		files     map[string]string // on disk
+		inputs    string            // recorded in the manifest
+		scaffolds []string          // recorded in the manifest
+		separator string
+		want      bool
+	}{
+		{"unchanged", map[string]string{"a.gen.go": content}, inputs, nil, "\n", true},
+		{"other inputs", map[string]string{"a.gen.go": content}, "before", nil, "\n", false},
+		{"incomplete generation", map[string]string{"a.gen.go": content}, "", nil, "\n", false},
+		{"edited", map[string]string{"a.gen.go": content + "// edited\n"}, inputs, nil, "\n", false},
+		{"removed", map[string]string{}, inputs, nil, "\n", false},
+		{"new timestamp", map[string]string{"a.gen.go": "// Generated at: 2025-01-01T00:00:00Z\npackage shop\n"}, inputs, nil, "\n", true},
+		{"crlf on disk, lf wanted", map[string]string{"a.gen.go": "// Generated at: 2024-01-01T00:00:00Z\r\npackage shop\r\n"}, inputs, nil, "\n", false},
+		{"lf on disk, crlf wanted", map[string]string{"a.gen.go": content}, inputs, nil, "\r\n", false},
+		{"crlf on disk and wanted", map[string]string{"a.gen.go": "// Generated at: 2024-01-01T00:00:00Z\r\npackage shop\r\n"}, inputs, nil, "\r\n", true},
+		{"scaffold edited", map[string]string{"a.gen.go": content, "a_test.go": "package shop\n// edited\n"}, inputs, []string{"a_test.go"}, "\n", true},
+		{"scaffold deleted", map[string]string{"a.gen.go": content}, inputs, []string{"a_test.go"}, "\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, data := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			m := &manifest{Inputs: tt.inputs, Files: map[string]string{"a.gen.go": contentHash(content)}, Scaffolds: tt.scaffolds}
+
+			if got := m.upToDate(dir, inputs, tt.separator); got != tt.want {
+				t.Errorf("upToDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := loadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 0 || m.Inputs != "" {
+		t.Fatalf("loadManifest() of a new directory = %+v, want an empty manifest", m)
+	}
+
+	m.record("v1.0.0", "inputs", map[string]string{filepath.Join("internal", "a.gen.go"): "hash"}, false)
+	if err := m.save(dir); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash, ok := loaded.tracked(filepath.Join("internal", "a.gen.go")); !ok || hash != "hash" {
+		t.Errorf("tracked() = %q, %v, want hash, true", hash, ok)
+	}
+	if loaded.Inputs != "" {
+		t.Errorf("Inputs = %q after an incomplete generation, want none", loaded.Inputs)
+	}
+}
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/navyarakshakarya/code-gen/generator"
+)
+
+// planFormat is the version of the plan file format
+const planFormat = 1
+
+// Actions of planned file operations
+const (
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionSkip   = "skip"
+)
+
+// plan describes the file operations generate would perform, so they can be
+// reviewed before apply performs them
+type plan struct {
+	Format    int           `json:"format"`
+	Version   string        `json:"version"`
+	OutputDir string        `json:"output_dir"`
+	Mode      string        `json:"mode"`
+	Inputs    string        `json:"inputs"`
+	Offline   bool          `json:"offline,omitempty"`
+	Files     []plannedFile `json:"files"`
+}
+
+// plannedFile is the operation planned for one generated file. Hashes are
+// content hashes; CurrentHash is empty when the file does not exist yet.
+type plannedFile struct {
+	Path        string `json:"path"`
+	Action      string `json:"action"`
+	Reason      string `json:"reason,omitempty"`
+	Hash        string `json:"hash"`
+	CurrentHash string `json:"current_hash,omitempty"`
+	Content     string `json:"content,omitempty"`
+	Scaffold    bool   `json:"scaffold,omitempty"` // written once, its content not recorded in the manifest
+}
+
+// newPlanCommand creates the plan command
+func (a *app) newPlanCommand() *cobra.Command {
+	opts := &generateOptions{}
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "plan [project-dir]",
+		Short: "Write the file operations generate would perform as JSON",
+		Long: `Render the code generate would write and describe, without writing it, what
+would happen to every file: create, update or skip, with the content hash of
+the generated file and of the file currently on disk.
+
+The plan includes the generated content, so it can be reviewed and later
+executed unchanged with code-gen apply.`,
+		Example: `  code-gen plan --out plan.json                # Plan generation for the current project
+  code-gen plan -o ./generated --out plan.json # -o is the output directory, as for generate
+  code-gen plan --force | jq '.files[].action' # Print the planned actions
+  code-gen apply plan.json                     # Execute a reviewed plan`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runPlan(cmd, args, opts, out)
+		},
+	}
+
+	opts.addFlags(cmd)
+	cmd.Flags().StringVar(&out, "out", "", "file to write the plan to (default: stdout)")
+
+	return cmd
+}
+
+// newApplyCommand creates the apply command
+func (a *app) newApplyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "apply <plan-file>",
+		Short: "Execute a plan written by code-gen plan",
+		Long: `Write the files of a plan created by code-gen plan. The plan is refused when
+any file it touches changed on disk since it was created; run plan again then.`,
+		Example: `  code-gen apply plan.json`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runApply(args[0])
+		},
+	}
+}
+
+// runPlan renders the generated files and writes the plan for them
+func (a *app) runPlan(cmd *cobra.Command, args []string, opts *generateOptions, out string) error {
+	logger := a.logger
+
+	// -o names the output directory of the generated code, not the plan
+	if cmd.Flags().Changed("output") && strings.EqualFold(filepath.Ext(a.outputDir), ".json") {
+		return withKind(kindConfigInvalid, fmt.Errorf("--output %s is the directory generated code is planned for; write the plan to a file with --out %s", a.outputDir, a.outputDir))
+	}
+
+	g, err := a.prepare(cmd, args, opts)
+	if err != nil {
+		return err
+	}
+	if g.projectInfo == nil {
+		return nil
+	}
+
+	m, err := loadManifest(g.outDir)
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+	inputs, err := inputsHash(a.version, g.options, g.separator, g.projectInfo)
+	if err != nil {
+		return withKind(kindTemplate, err)
+	}
+
+	generationStart := time.Now()
+	results, err := g.generator.Generate(g.projectInfo)
+	a.stats.phase("generation", generationStart)
+	if err != nil {
+		return withKind(kindTemplate, fmt.Errorf("code generation failed: %w", err))
+	}
+	applyLineEndings(results, g.separator)
+	statuses := planStatuses(results, g.outDir, g.force, m)
+
+	p := &plan{
+		Format:    planFormat,
+		Version:   a.version,
+		OutputDir: relativeToWorkingDir(g.outDir),
+		Mode:      g.mode,
+		Inputs:    inputs,
+		Offline:   g.offline,
+	}
+	counts := make(map[string]int)
+	for _, result := range results {
+		file := plannedFile{
+			Path:     filepath.ToSlash(result.Filename),
+			Hash:     contentHash(result.Content),
+			Scaffold: result.Scaffold,
+		}
+		if hash, err := fileHash(filepath.Join(g.outDir, result.Filename)); err == nil {
+			file.CurrentHash = hash
+		}
+
+		switch statuses[result.Filename] {
+		case statusNew:
+			file.Action, file.Content = actionCreate, result.Content
+		case statusOverwrite:
+			file.Action, file.Content = actionUpdate, result.Content
+		case statusUpToDate:
+			file.Action, file.Reason = actionSkip, "up to date"
+		case statusKept:
+			file.Action, file.Reason = actionSkip, "scaffold, not overwritten"
+		default:
+			file.Action, file.Reason = actionSkip, "modified, use --force to overwrite"
+		}
+		counts[file.Action]++
+		p.Files = append(p.Files, file)
+	}
+
+	upToDate, kept := 0, 0
+	for _, status := range statuses {
+		switch status {
+		case statusUpToDate:
+			upToDate++
+		case statusKept:
+			kept++
+		}
+	}
+	a.stats.files(len(results), 0, upToDate, counts[actionSkip]-upToDate-kept)
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return withKind(kindGeneral, fmt.Errorf("failed to encode the plan: %w", err))
+	}
+	data = append(data, '\n')
+
+	if out == "" {
+		os.Stdout.Write(data)
+	} else if err := os.WriteFile(out, data, 0644); err != nil {
+		return withKind(kindIO, fmt.Errorf("failed to write the plan: %w", err))
+	}
+
+	logger.Success("Plan: %d to create, %d to update, %d to skip", counts[actionCreate], counts[actionUpdate], counts[actionSkip])
+	if out != "" {
+		logger.Info("Run: code-gen apply %s", out)
+	}
+	return nil
+}
+
+// runApply executes the plan in planFile
+func (a *app) runApply(planFile string) error {
+	logger := a.logger
+
+	p, err := readPlan(planFile)
+	if err != nil {
+		return withKind(kindConfigInvalid, err)
+	}
+	outDir := p.OutputDir
+
+	release, err := acquireLock(outDir)
+	if err != nil {
+		if errors.Is(err, errLocked) {
+			return withKind(kindLocked, err)
+		}
+		return withKind(kindIO, err)
+	}
+	defer release()
+
+	m, err := loadManifest(outDir)
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+
+	// The plan only holds while the files it was made against are unchanged
+	var results []*generator.GeneratedFile
+	statuses := make(map[string]fileStatus)
+	hashes := make(map[string]string)
+	upToDate, skipped := 0, 0
+	var scaffolds []string
+	for _, file := range p.Files {
+		filename := filepath.FromSlash(file.Path)
+		if file.Scaffold {
+			scaffolds = append(scaffolds, filename)
+		}
+		hash, err := fileHash(filepath.Join(outDir, filename))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return withKind(kindIO, fmt.Errorf("failed to read %s: %w", filename, err))
+		}
+		if hash != file.CurrentHash {
+			return withKind(kindConflict, fmt.Errorf("%s changed since the plan was created; run code-gen plan again", filename))
+		}
+
+		switch file.Action {
+		case actionCreate:
+			statuses[filename] = statusNew
+		case actionUpdate:
+			statuses[filename] = statusOverwrite
+		default:
+			if file.Scaffold {
+				continue
+			}
+			if file.CurrentHash == file.Hash {
+				hashes[filename] = file.Hash
+				upToDate++
+			} else {
+				skipped++
+			}
+			continue
+		}
+		results = append(results, &generator.GeneratedFile{Filename: filename, Content: file.Content, Scaffold: file.Scaffold})
+	}
+
+	writeStart := time.Now()
+	written, _, err := writeFiles(results, outDir, statuses, logger)
+	a.stats.phase("write", writeStart)
+	if err != nil {
+		return withKind(kindIO, fmt.Errorf("%w (no files were changed)", err))
+	}
+	for _, result := range results {
+		if !result.Scaffold {
+			hashes[result.Filename] = contentHash(result.Content)
+		}
+	}
+
+	m.record(p.Version, p.Inputs, hashes, skipped == 0)
+	m.recordScaffolds(scaffolds)
+	a.stats.files(len(p.Files), len(written), upToDate, skipped)
+	if err := m.save(outDir); err != nil {
+		return withKind(kindIO, fmt.Errorf("failed to write %s: %w", manifestFile, err))
+	}
+
+	logger.Success("Plan applied!")
+	logger.Info("Generated %d files, skipped %d", len(written), len(p.Files)-len(written))
+
+	if len(written) > 0 {
+		hooksStart := time.Now()
+		err := runHooks("post_generate", a.config.Hooks.PostGenerate, outDir, p.Offline, logger)
+		a.stats.phase("hooks", hooksStart)
+		if err != nil {
+			return withKind(kindHook, err)
+		}
+		if p.Mode == generator.ModeImplementations {
+			checkWire(results, outDir, p.Offline, logger)
+		}
+	}
+	return nil
+}
+
+// readPlan reads and checks a plan file
+func readPlan(path string) (*plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var p plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	if p.Format != planFormat {
+		return nil, fmt.Errorf("plan %s has format %d, this code-gen reads format %d", path, p.Format, planFormat)
+	}
+	if p.OutputDir == "" {
+		return nil, fmt.Errorf("plan %s has no output directory", path)
+	}
+
+	for _, file := range p.Files {
+		if !filepath.IsLocal(filepath.FromSlash(file.Path)) {
+			return nil, fmt.Errorf("plan %s writes outside the output directory: %s", path, file.Path)
+		}
+		switch file.Action {
+		case actionCreate, actionUpdate:
+			if contentHash(file.Content) != file.Hash {
+				return nil, fmt.Errorf("plan %s: content of %s does not match its hash", path, file.Path)
+			}
+		case actionSkip:
+		default:
+			return nil, fmt.Errorf("plan %s: unknown action %q for %s", path, file.Action, file.Path)
+		}
+	}
+	return &p, nil
+}
+
+// relativeToWorkingDir returns path relative to the working directory when it
+// lies within it, so plans can be applied from another checkout
+func relativeToWorkingDir(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil || !filepath.IsLocal(rel) && rel != "." {
+		return path
+	}
+	return rel
+}
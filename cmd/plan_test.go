@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/navyarakshakarya/code-gen/config"
+	"github.com/navyarakshakarya/code-gen/logger"
+)
+
+// writePlan writes p as a plan file and returns its path
+func writePlan(t *testing.T, p *plan) string {
+	t.Helper()
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadPlan(t *testing.T) {
+	created := plannedFile{Path: "a.gen.go", Action: actionCreate, Hash: contentHash("package a\n"), Content: "package a\n"}
+
+	tests := []struct {
+		name    string
+		modify  func(p *plan)
+		wantErr string
+	}{
+		{"valid", func(p *plan) {}, ""},
+		{"skipped file without content", func(p *plan) {
+			p.Files = append(p.Files, plannedFile{Path: "b.gen.go", Action: actionSkip, Hash: "hash", Reason: "up to date"})
+		}, ""},
+		{"other format", func(p *plan) { p.Format = planFormat + 1 }, "this code-gen reads format"},
+		{"no output directory", func(p *plan) { p.OutputDir = "" }, "no output directory"},
+		{"parent directory", func(p *plan) { p.Files[0].Path = "../a.gen.go" }, "writes outside the output directory"},
+		{"absolute path", func(p *plan) { p.Files[0].Path = "/etc/a.gen.go" }, "writes outside the output directory"},
+		{"edited content", func(p *plan) { p.Files[0].Content = "package b\n" }, "does not match its hash"},
+		{"unknown action", func(p *plan) { p.Files[0].Action = "delete" }, "unknown action"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &plan{Format: planFormat, OutputDir: "out", Files: []plannedFile{created}}
+			tt.modify(p)
+
+			_, err := readPlan(writePlan(t, p))
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("readPlan() = %v, want no error", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("readPlan() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "plan.json")
+		if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readPlan(path); err == nil || !strings.Contains(err.Error(), "failed to parse plan") {
+			t.Errorf("readPlan() = %v, want a parse error", err)
+		}
+	})
+}
+
+func TestRunApply(t *testing.T) {
+	const oldContent, newContent = "package shop\n", "package shop\n\nvar x = 1\n"
+
+	tests := []struct {
+		name     string
+		disk     string // content of update.gen.go when applying, "" when missing
+		create   string // content of create.gen.go when applying, "" when missing
+		conflict bool
+	}{
+		{"unchanged", oldContent, "", false},
+		{"updated file edited", oldContent + "// edited\n", "", true},
+		{"updated file removed", "", "", true},
+		{"created file appeared", oldContent, "package shop\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			p := &plan{
+				Format:    planFormat,
+				Version:   "v1.0.0",
+				OutputDir: dir,
+				Inputs:    "inputs",
+				Files: []plannedFile{
+					{Path: "create.gen.go", Action: actionCreate, Hash: contentHash(newContent), Content: newContent},
+					{Path: "update.gen.go", Action: actionUpdate, Hash: contentHash(newContent), CurrentHash: contentHash(oldContent), Content: newContent},
+				},
+			}
+			planFile := writePlan(t, p)
+			if tt.disk != "" {
+				writeFile(t, filepath.Join(dir, "update.gen.go"), tt.disk)
+			}
+			if tt.create != "" {
+				writeFile(t, filepath.Join(dir, "create.gen.go"), tt.create)
+			}
+
+			a := &app{version: "v1.0.0", logger: logger.New(false, true), config: &config.Config{}}
+			err := a.runApply(planFile)
+
+			if !tt.conflict {
+				if err != nil {
+					t.Fatalf("runApply() = %v, want no error", err)
+				}
+				for _, name := range []string{"create.gen.go", "update.gen.go"} {
+					if got := readFile(t, filepath.Join(dir, name)); got != newContent {
+						t.Errorf("%s = %q, want %q", name, got, newContent)
+					}
+				}
+				m, err := loadManifest(dir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if m.Inputs != "inputs" || len(m.Files) != 2 {
+					t.Errorf("manifest = %+v, want the plan inputs and both files", m)
+				}
+				return
+			}
+
+			if kindOf(err) != kindConflict {
+				t.Fatalf("runApply() = %v (%s), want a %s error", err, kindOf(err), kindConflict)
+			}
+			// Nothing is written once any file conflicts
+			if tt.create == "" {
+				if _, err := os.Stat(filepath.Join(dir, "create.gen.go")); !os.IsNotExist(err) {
+					t.Errorf("create.gen.go was written despite the conflict")
+				}
+			}
+			if tt.disk != "" {
+				if got := readFile(t, filepath.Join(dir, "update.gen.go")); got != tt.disk {
+					t.Errorf("update.gen.go = %q, want it left at %q", got, tt.disk)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(dir, manifestFile)); !os.IsNotExist(err) {
+				t.Errorf("%s was written despite the conflict", manifestFile)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/navyarakshakarya/code-gen/generator"
+)
+
+// fileStatus describes what writing a generated file will do
+type fileStatus string
+
+const (
+	statusNew       fileStatus = "new"
+	statusOverwrite fileStatus = "overwrite"
+	statusSkip      fileStatus = "exists, skip"
+	statusUpToDate  fileStatus = "up to date"
+	statusKept      fileStatus = "exists, kept"
+)
+
+// ANSI colors used for the preview tree and diffs
+const (
+	colorReset  = "\033[0m"
+	colorBold   = "\033[1m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+	colorGray   = "\033[90m"
+)
+
+// treeNode is a directory or file in the preview tree
+type treeNode struct {
+	name     string
+	file     *generator.GeneratedFile
+	status   fileStatus
+	children map[string]*treeNode
+}
+
+// planStatuses determines the status of every generated file in outputDir.
+// Files recorded in the manifest and not edited since are regenerated without
+// force, or left alone when their bytes, line endings included, would not
+// change unless forced. Existing scaffolds are always kept.
+func planStatuses(results []*generator.GeneratedFile, outputDir string, force bool, m *manifest) map[string]fileStatus {
+	statuses := make(map[string]fileStatus, len(results))
+	for _, result := range results {
+		unmodified := m.unmodified(outputDir, result.Filename)
+		hash, _ := m.tracked(result.Filename)
+
+		switch _, err := os.Stat(filepath.Join(outputDir, result.Filename)); {
+		case err != nil:
+			statuses[result.Filename] = statusNew
+		case result.Scaffold:
+			statuses[result.Filename] = statusKept
+		case unmodified && !force && hash == contentHash(result.Content) && sameBytes(filepath.Join(outputDir, result.Filename), result.Content):
+			statuses[result.Filename] = statusUpToDate
+		case force || unmodified:
+			statuses[result.Filename] = statusOverwrite
+		default:
+			statuses[result.Filename] = statusSkip
+		}
+	}
+	return statuses
+}
+
+// printTree writes the planned directory tree rooted at outputDir to w, with
+// ASCII branches when plain
+func printTree(w io.Writer, outputDir string, results []*generator.GeneratedFile, statuses map[string]fileStatus, color, plain bool) {
+	root := &treeNode{children: make(map[string]*treeNode)}
+	for _, result := range results {
+		node := root
+		for _, part := range strings.Split(filepath.ToSlash(result.Filename), "/") {
+			child, ok := node.children[part]
+			if !ok {
+				child = &treeNode{name: part, children: make(map[string]*treeNode)}
+				node.children[part] = child
+			}
+			node = child
+		}
+		node.file = result
+		node.status = statuses[result.Filename]
+	}
+
+	fmt.Fprintln(w, outputDir)
+	printChildren(w, root, "", color, plain)
+}
+
+func printChildren(w io.Writer, node *treeNode, prefix string, color, plain bool) {
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		child := node.children[name]
+		branch, indent := treeBranch(i == len(names)-1, plain)
+
+		if child.file == nil {
+			fmt.Fprintf(w, "%s%s%s/\n", prefix, branch, child.name)
+			printChildren(w, child, prefix+indent, color, plain)
+			continue
+		}
+
+		label := fmt.Sprintf("%s (%s, %d lines)", child.name, child.status, child.file.LineCount)
+		if color {
+			label = statusColor(child.status) + label + colorReset
+		}
+		fmt.Fprintf(w, "%s%s%s\n", prefix, branch, label)
+	}
+}
+
+// treeBranch returns the branch drawn before a tree entry and the indent of
+// its children, in ASCII when plain
+func treeBranch(last, plain bool) (branch, indent string) {
+	switch {
+	case last && plain:
+		return "`-- ", "    "
+	case last:
+		return "└── ", "    "
+	case plain:
+		return "|-- ", "|   "
+	default:
+		return "├── ", "│   "
+	}
+}
+
+func statusColor(status fileStatus) string {
+	switch status {
+	case statusNew:
+		return colorGreen
+	case statusOverwrite:
+		return colorYellow
+	default:
+		return colorGray
+	}
+}
+
+// confirm asks a yes/no question and reports whether the answer is one of
+// the accepted yes answers of p
+func confirm(in io.Reader, out io.Writer, question string, p prompts) (bool, error) {
+	fmt.Fprintf(out, "%s %s: ", question, p.choices)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	for _, yes := range p.yes {
+		if answer == strings.ToLower(yes) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isTerminal reports whether f is connected to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// useColor reports whether colored output should be written to stdout
+func (a *app) useColor() bool {
+	return !a.plain && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+// prompts are the strings of interactive prompts
+type prompts struct {
+	confirmWrite string
+	choices      string
+	yes          []string
+}
+
+// defaultPrompts are the English prompt strings
+var defaultPrompts = prompts{
+	confirmWrite: "Write these files?",
+	choices:      "[y/N]",
+	yes:          []string{"y", "yes"},
+}
+
+// prompts returns the prompt strings, with those set in the configuration
+// file replacing the defaults
+func (a *app) prompts() prompts {
+	p := defaultPrompts
+	if a.config.Prompts.ConfirmWrite != "" {
+		p.confirmWrite = a.config.Prompts.ConfirmWrite
+	}
+	if a.config.Prompts.Choices != "" {
+		p.choices = a.config.Prompts.Choices
+	}
+	if len(a.config.Prompts.Yes) > 0 {
+		p.yes = a.config.Prompts.Yes
+	}
+	return p
+}
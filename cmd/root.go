@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/navyarakshakarya/code-gen/config"
+	"github.com/navyarakshakarya/code-gen/logger"
+)
+
+const banner = `
+ ██████╗ ██████╗ ██████╗ ███████╗      ██████╗ ███████╗███╗   ██╗
+██╔════╝██╔═══██╗██╔══██╗██╔════╝     ██╔════╝ ██╔════╝████╗  ██║
+██║     ██║   ██║██║  ██║█████╗       ██║  ███╗█████╗  ██╔██╗ ██║
+██║     ██║   ██║██║  ██║██╔══╝       ██║   ██║██╔══╝  ██║╚██╗██║
+╚██████╗╚██████╔╝██████╔╝███████╗     ╚██████╔╝███████╗██║ ╚████║
+ ╚═════╝ ╚═════╝ ╚═════╝ ╚══════╝      ╚═════╝ ╚══════╝╚═╝  ╚═══╝
+
+Go Clean Architecture Code Generator %s
+`
+
+const plainBanner = "code-gen - Go Clean Architecture Code Generator %s\n"
+
+// configEnv names the configuration file when --config is not given
+const configEnv = "CODEGEN_CONFIG"
+
+// skipConfigAnnotation marks commands that do not load the configuration
+const skipConfigAnnotation = "code-gen/skip-config"
+
+// app holds state shared by all commands
+type app struct {
+	version    string
+	build      BuildInfo
+	configPath string
+	noDefaults bool // ignore the organization defaults file
+	offline    bool // --offline was given: remote configurations and releases are not fetched
+	statsPath  string
+	outputDir  string
+	verbose    bool
+	plain      bool
+
+	logger *logger.Logger
+	config *config.Config
+	stats  *runStats // nil unless stats are enabled
+}
+
+// Execute runs the root command and exits with a code describing the failure
+func Execute(build BuildInfo) {
+	a := &app{version: build.Version, build: build.resolve()}
+
+	err := a.newRootCommand().Execute()
+	if statsErr := a.stats.save(err); statsErr != nil {
+		a.logger.Warning("%v", statsErr)
+	}
+	if err != nil {
+		if a.logger == nil {
+			a.logger = logger.New(a.verbose, a.plain)
+		}
+		a.logger.Error("%v", err)
+		writeErrorReport(os.Stderr, err)
+		os.Exit(kindOf(err).exitCode())
+	}
+}
+
+// newRootCommand builds the command tree with its persistent flags
+func (a *app) newRootCommand() *cobra.Command {
+	genOpts := &generateOptions{}
+
+	root := &cobra.Command{
+		Use:   "code-gen [project-dir]",
+		Short: "Go Clean Architecture Code Generator",
+		Long: `code-gen analyzes a Go project and generates clean architecture
+implementations, a dependency injection factory and Wire providers
+for the interfaces it finds.
+
+Running code-gen without a subcommand is the same as "code-gen generate".`,
+		Version:           a.version,
+		Args:              cobra.MaximumNArgs(1),
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		PersistentPreRunE: a.setup,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runGenerate(cmd, args, genOpts)
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.configPath, "config", "", "path or URL of a JSON configuration file (https://..., git::<repo>//<file>?ref=<ref>, or git::<repo>/<file> for a file at its root); defaults to $CODEGEN_CONFIG")
+	flags.BoolVar(&a.noDefaults, "no-defaults", false, "ignore the organization defaults file (~/.config/codegen/defaults.yaml)")
+	flags.StringVar(&a.statsPath, "stats", "", "append a record of the run (durations, file counts, failures) to this local JSON Lines file")
+	flags.StringVarP(&a.outputDir, "output", "o", "", "output directory (default: project directory)")
+	flags.BoolVarP(&a.verbose, "verbose", "v", false, "enable verbose output")
+	flags.BoolVar(&a.plain, "plain", false, "plain ASCII output without emoji, box drawing or colors")
+	flags.BoolVar(&a.plain, "no-emoji", false, "same as --plain")
+
+	root.SetVersionTemplate(a.build.String() + "\n")
+
+	genOpts.addFlags(root)
+	genOpts.addAllowSkippedFlag(root)
+	root.AddCommand(a.newGenerateCommand())
+	root.AddCommand(a.newExtractCommand())
+	root.AddCommand(a.newDepsCommand())
+	root.AddCommand(a.newDoctorCommand())
+	root.AddCommand(a.newCleanCommand())
+	root.AddCommand(a.newDiffCommand())
+	root.AddCommand(a.newListCommand())
+	root.AddCommand(a.newPlanCommand())
+	root.AddCommand(a.newApplyCommand())
+	root.AddCommand(a.newValidateCommand())
+	root.AddCommand(a.newUpgradeCommand())
+	root.AddCommand(a.newSelfUpdateCommand())
+
+	return root
+}
+
+// setup initializes the logger and loads the configuration file
+func (a *app) setup(cmd *cobra.Command, args []string) error {
+	// Terminals that cannot render Unicode get plain output too
+	if os.Getenv("TERM") == "dumb" {
+		a.plain = true
+	}
+	a.logger = logger.New(a.verbose, a.plain)
+
+	if a.verbose {
+		if a.plain {
+			fmt.Printf(plainBanner, a.version)
+		} else {
+			fmt.Printf(banner, a.version)
+		}
+	}
+
+	// Commands without --offline leave it false
+	a.offline, _ = cmd.Flags().GetBool("offline")
+
+	// --config takes precedence over the environment
+	if !cmd.Flags().Changed("config") {
+		if path := os.Getenv(configEnv); path != "" {
+			a.configPath = path
+		}
+	}
+	// The project configuration is merged over the organization defaults;
+	// commands checking the configuration read it themselves
+	a.config = &config.Config{}
+	if cmd.Annotations[skipConfigAnnotation] == "" {
+		var defaultsPath string
+		if !a.noDefaults {
+			if path, err := config.DefaultsPath(); err == nil {
+				defaultsPath = path
+				if _, err := os.Stat(path); err == nil {
+					a.logger.Info("Using defaults from %s", path)
+				}
+			}
+		}
+		cfg, err := config.LoadWithDefaults(a.configPath, defaultsPath, a.offline)
+		if err != nil {
+			return withKind(kindConfigInvalid, err)
+		}
+		a.config = cfg
+	}
+
+	// Stats are opt-in, by flag or configuration
+	statsPath := a.statsPath
+	if statsPath == "" && a.config.Stats != "" {
+		statsPath = a.config.Stats
+		if !filepath.IsAbs(statsPath) {
+			statsPath = filepath.Join(a.configDir(), statsPath)
+		}
+	}
+	a.stats = startStats(statsPath, a.version, cmd.Name())
+
+	return nil
+}
+
+// configDir returns the directory relative paths in the configuration file
+// are resolved against; those of remote configurations resolve against the
+// working directory
+func (a *app) configDir() string {
+	if a.configPath == "" || config.IsRemote(a.configPath) {
+		return "."
+	}
+	return filepath.Dir(a.configPath)
+}
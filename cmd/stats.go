@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// runStats is the record of one run appended to the stats file. Stats are
+// only kept when a stats file is configured and never leave the machine.
+type runStats struct {
+	Time       time.Time        `json:"time"`
+	Version    string           `json:"version"`
+	Command    string           `json:"command"`
+	Project    string           `json:"project,omitempty"`
+	DurationMS int64            `json:"duration_ms"`
+	PhasesMS   map[string]int64 `json:"phases_ms,omitempty"` // analysis, generation, write, hooks
+	Interfaces int              `json:"interfaces,omitempty"`
+	Files      int              `json:"files,omitempty"` // generated in memory
+	Written    int              `json:"written,omitempty"`
+	UpToDate   int              `json:"up_to_date,omitempty"`
+	Skipped    int              `json:"skipped,omitempty"`
+	Error      *statsError      `json:"error,omitempty"`
+
+	path string
+}
+
+// statsError is the failure of a run
+type statsError struct {
+	Kind    errorKind `json:"kind"`
+	Message string    `json:"message"`
+}
+
+// startStats begins the record of a run of command when path is set
+func startStats(path, version, command string) *runStats {
+	if path == "" {
+		return nil
+	}
+	return &runStats{
+		Time:     time.Now(),
+		Version:  version,
+		Command:  command,
+		PhasesMS: make(map[string]int64),
+		path:     path,
+	}
+}
+
+// phase records the duration of a phase that began at start. Like the other
+// runStats methods it does nothing when stats are disabled.
+func (s *runStats) phase(name string, start time.Time) {
+	if s == nil {
+		return
+	}
+	s.PhasesMS[name] += time.Since(start).Milliseconds()
+}
+
+// files records the outcome of writing the generated files
+func (s *runStats) files(generated, written, upToDate, skipped int) {
+	if s == nil {
+		return
+	}
+	s.Files, s.Written, s.UpToDate, s.Skipped = generated, written, upToDate, skipped
+}
+
+// project records the analyzed project
+func (s *runStats) project(dir string, interfaces int) {
+	if s == nil {
+		return
+	}
+	s.Project, s.Interfaces = dir, interfaces
+}
+
+// save appends the record, with the outcome of the run, to the stats file
+func (s *runStats) save(runErr error) error {
+	if s == nil {
+		return nil
+	}
+	s.DurationMS = time.Since(s.Time).Milliseconds()
+	if runErr != nil {
+		s.Error = &statsError{Kind: kindOf(runErr), Message: runErr.Error()}
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to write stats: %w", err)
+		}
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write stats: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write stats: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// releasesURL is the GitHub API endpoint of the latest release
+const releasesURL = "https://api.github.com/repos/navyarakshakarya/code-gen/releases/latest"
+
+// checksumsAsset is the release asset listing the SHA-256 of every binary
+const checksumsAsset = "checksums.txt"
+
+// errUpdateOffline is returned by self-update in offline mode, as
+// config.ErrOffline is for remote configurations
+var errUpdateOffline = errors.New("releases cannot be fetched offline; install a release by hand or drop --offline")
+
+// release is the subset of the GitHub release API response self-update uses
+type release struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// assetURL returns the download URL of the named asset
+func (r *release) assetURL(name string) (string, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset.URL, true
+		}
+	}
+	return "", false
+}
+
+// newSelfUpdateCommand creates the self-update command
+func (a *app) newSelfUpdateCommand() *cobra.Command {
+	var check, force bool
+
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update code-gen to the latest GitHub release",
+		Long: `Check GitHub for the latest code-gen release and replace the running binary
+with the build for this platform, after verifying its SHA-256 checksum.
+
+Binaries installed by a package manager (Homebrew, Scoop) are left alone;
+update them through the package manager instead.`,
+		Example: `  code-gen self-update           # Install the latest release
+  code-gen self-update --check   # Only report whether an update is available`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runSelfUpdate(check, force)
+		},
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "only report whether a newer release is available")
+	cmd.Flags().BoolVar(&force, "force", false, "reinstall even when the latest release is not newer")
+	cmd.Flags().Bool("offline", false, "work without network access: refuse to fetch releases")
+
+	return cmd
+}
+
+// runSelfUpdate replaces the running binary with the latest release
+func (a *app) runSelfUpdate(check, force bool) error {
+	logger := a.logger
+	if a.offline || a.config.Offline {
+		return withKind(kindGeneral, errUpdateOffline)
+	}
+	client := &http.Client{Timeout: 60 * time.Second}
+
+	latest, err := latestRelease(client)
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+
+	newer := newerVersion(latest.TagName, a.version)
+	if !newer && !force {
+		logger.Success("code-gen %s is up to date", a.version)
+		return nil
+	}
+	if check {
+		logger.Info("Current version: %s", a.version)
+		logger.Success("code-gen %s is available, run code-gen self-update to install it", latest.TagName)
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return withKind(kindIO, fmt.Errorf("failed to locate the running binary: %w", err))
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return withKind(kindIO, fmt.Errorf("failed to locate the running binary: %w", err))
+	}
+	if manager := packageManager(executable); manager != "" {
+		return withKind(kindGeneral, fmt.Errorf("code-gen was installed with %s; update it with %s instead", manager, manager))
+	}
+
+	name := assetName(runtime.GOOS, runtime.GOARCH)
+	binaryURL, ok := latest.assetURL(name)
+	if !ok {
+		return withKind(kindGeneral, fmt.Errorf("release %s has no binary for %s/%s", latest.TagName, runtime.GOOS, runtime.GOARCH))
+	}
+	checksumsURL, ok := latest.assetURL(checksumsAsset)
+	if !ok {
+		return withKind(kindGeneral, fmt.Errorf("release %s has no %s to verify the binary", latest.TagName, checksumsAsset))
+	}
+
+	checksums, err := download(client, checksumsURL)
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+	want, ok := checksumOf(checksums, name)
+	if !ok {
+		return withKind(kindGeneral, fmt.Errorf("%s of release %s does not list %s", checksumsAsset, latest.TagName, name))
+	}
+
+	logger.Info("Downloading %s", binaryURL)
+	binary, err := download(client, binaryURL)
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+	sum := sha256.Sum256(binary)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return withKind(kindGeneral, fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want))
+	}
+
+	if err := replaceExecutable(executable, binary); err != nil {
+		return withKind(kindIO, err)
+	}
+
+	logger.Success("Updated code-gen %s -> %s", a.version, latest.TagName)
+	return nil
+}
+
+// latestRelease fetches the latest release from the GitHub API
+func latestRelease(client *http.Client) (*release, error) {
+	content, err := download(client, releasesURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest release
+	if err := json.Unmarshal(content, &latest); err != nil {
+		return nil, fmt.Errorf("failed to parse the latest release: %w", err)
+	}
+	return &latest, nil
+}
+
+// download returns the body of a GET request to url
+func download(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	return content, nil
+}
+
+// assetName returns the release asset name of the binary for a platform, as
+// built by the release workflow
+func assetName(goos, goarch string) string {
+	name := fmt.Sprintf("code-gen-%s-%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// checksumOf returns the hex SHA-256 listed for name in sha256sum output
+func checksumOf(checksums []byte, name string) (string, bool) {
+	scanner := bufio.NewScanner(strings.NewReader(string(checksums)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
+
+// packageManager returns the package manager owning the binary at path, if any
+func packageManager(path string) string {
+	slashed := filepath.ToSlash(strings.ToLower(path))
+	switch {
+	case strings.Contains(slashed, "/cellar/") || strings.Contains(slashed, "/homebrew/"):
+		return "Homebrew"
+	case strings.Contains(slashed, "/scoop/"):
+		return "Scoop"
+	}
+	return ""
+}
+
+// replaceExecutable writes binary next to the executable and swaps it in. The
+// running binary is moved aside first, as Windows cannot overwrite it.
+func replaceExecutable(executable string, binary []byte) error {
+	dir := filepath.Dir(executable)
+	staged, err := os.CreateTemp(dir, ".code-gen-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to stage the update in %s: %w", dir, err)
+	}
+	stagedPath := staged.Name()
+	defer os.Remove(stagedPath)
+
+	if _, err := staged.Write(binary); err != nil {
+		staged.Close()
+		return fmt.Errorf("failed to stage the update: %w", err)
+	}
+	if err := staged.Close(); err != nil {
+		return fmt.Errorf("failed to stage the update: %w", err)
+	}
+	if err := os.Chmod(stagedPath, 0755); err != nil {
+		return fmt.Errorf("failed to stage the update: %w", err)
+	}
+
+	old := executable + ".old"
+	os.Remove(old)
+	if err := os.Rename(executable, old); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", executable, err)
+	}
+	if err := os.Rename(stagedPath, executable); err != nil {
+		// Put the running binary back
+		os.Rename(old, executable)
+		return fmt.Errorf("failed to replace %s: %w", executable, err)
+	}
+	// Windows keeps the running binary locked; it is removed by the next update
+	os.Remove(old)
+	return nil
+}
+
+// newerVersion reports whether the release tag is a newer version than
+// current. Versions that are not vMAJOR.MINOR.PATCH, such as development
+// builds, are always considered older.
+func newerVersion(tag, current string) bool {
+	latest, ok := parseVersion(tag)
+	if !ok {
+		return false
+	}
+	running, ok := parseVersion(current)
+	if !ok {
+		return true
+	}
+	for i := range latest {
+		if latest[i] != running[i] {
+			return latest[i] > running[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses vMAJOR.MINOR.PATCH, ignoring pre-release and build suffixes
+func parseVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+	version, _, _ = strings.Cut(strings.TrimPrefix(version, "v"), "-")
+	version, _, _ = strings.Cut(version, "+")
+
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return parsed, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+	return parsed, true
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/navyarakshakarya/code-gen/config"
+	"github.com/navyarakshakarya/code-gen/logger"
+)
+
+func TestSelfUpdateOffline(t *testing.T) {
+	tests := []struct {
+		name    string
+		offline bool
+		config  config.Config
+	}{
+		{"flag", true, config.Config{}},
+		{"configuration", false, config.Config{Offline: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &app{version: "v1.0.0", offline: tt.offline, config: &tt.config, logger: logger.New(false, true)}
+			err := a.runSelfUpdate(true, false)
+			if !errors.Is(err, errUpdateOffline) || kindOf(err) != kindGeneral {
+				t.Errorf("runSelfUpdate() offline = %v (%s), want errUpdateOffline", err, kindOf(err))
+			}
+		})
+	}
+}
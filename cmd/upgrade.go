@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/navyarakshakarya/code-gen/generator"
+	"github.com/navyarakshakarya/code-gen/logger"
+)
+
+// newUpgradeCommand creates the upgrade command
+func (a *app) newUpgradeCommand() *cobra.Command {
+	opts := &generateOptions{upgrade: true}
+
+	cmd := &cobra.Command{
+		Use:   "upgrade [project-dir]",
+		Short: "Re-render generated code with this version of code-gen",
+		Long: `Regenerate a project generated by an earlier code-gen release, so it picks up
+improvements of the current one. Generated files unchanged since they were
+generated are rewritten; files you edited are listed and kept, unless named
+with --overwrite. Files the current version no longer generates are reported.
+
+Pass the flags used when the project was generated, as for generate.`,
+		Example: `  code-gen upgrade                                   # Upgrade the current project
+  code-gen diff                                      # Review changes to the kept files
+  code-gen upgrade --overwrite user_repository.gen.go  # Also take the new version of an edited file`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runGenerate(cmd, args, opts)
+		},
+	}
+
+	opts.addFlags(cmd)
+	cmd.Flags().StringSliceVar(&opts.overwrite, "overwrite", nil, "edited generated files to overwrite anyway, relative to the output directory (comma separated)")
+
+	return cmd
+}
+
+// overwriteEdited marks the named edited files to be overwritten. Names
+// must be generated files.
+func overwriteEdited(statuses map[string]fileStatus, results []*generator.GeneratedFile, names []string) error {
+	for _, name := range names {
+		found := false
+		for _, result := range results {
+			if filepath.ToSlash(result.Filename) != filepath.ToSlash(filepath.Clean(name)) {
+				continue
+			}
+			found = true
+			if statuses[result.Filename] == statusSkip {
+				statuses[result.Filename] = statusOverwrite
+			}
+		}
+		if !found {
+			return fmt.Errorf("--overwrite %s: not a generated file", name)
+		}
+	}
+	return nil
+}
+
+// reportUpgrade lists the edited files an upgrade kept and the recorded files
+// in outputDir the current version no longer generates
+func reportUpgrade(results []*generator.GeneratedFile, outputDir string, statuses map[string]fileStatus, m *manifest, logger *logger.Logger) {
+	var generated []string
+	kept := 0
+	for _, result := range results {
+		generated = append(generated, filepath.ToSlash(result.Filename))
+		if statuses[result.Filename] == statusSkip {
+			kept++
+		}
+	}
+
+	var obsolete []string
+	for filename := range m.Files {
+		if slices.Contains(generated, filename) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(filename))); err == nil {
+			obsolete = append(obsolete, filename)
+		}
+	}
+	sort.Strings(obsolete)
+	for _, filename := range obsolete {
+		logger.Warning("No longer generated: %s", filepath.FromSlash(filename))
+	}
+
+	if kept > 0 {
+		logger.Warning("Kept %d edited files; review the new versions with code-gen diff and take them with --overwrite", kept)
+	}
+	if len(obsolete) > 0 {
+		logger.Warning("Remove files that are no longer generated once nothing refers to them")
+	}
+}
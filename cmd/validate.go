@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/navyarakshakarya/code-gen/config"
+	"github.com/navyarakshakarya/code-gen/generator"
+)
+
+// configIssue is a problem found in a configuration file, located by the JSON
+// path of the offending value
+type configIssue struct {
+	path    string
+	message string
+}
+
+// newValidateCommand creates the validate command
+func (a *app) newValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [config-file]",
+		Short: "Check a configuration file without generating code",
+		Long: `Check a configuration file for unknown fields, values of the wrong type and
+invalid options, such as unknown modes, layers or style settings, and report
+every problem with the JSON path of the value. The file may be a URL, as for
+--config, or a YAML defaults file, and defaults to the --config file.`,
+		Example: `  code-gen validate code-gen.json
+  CODEGEN_CONFIG=code-gen.json code-gen validate`,
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: map[string]string{skipConfigAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := a.configPath
+			if len(args) > 0 {
+				path = args[0]
+			}
+			if path == "" {
+				return withKind(kindConfigInvalid, fmt.Errorf("no configuration file given: pass one, --config or %s", configEnv))
+			}
+			return a.runValidate(path)
+		},
+	}
+}
+
+// runValidate reports the issues of the configuration at path
+func (a *app) runValidate(path string) error {
+	var content []byte
+	var err error
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		// Defaults files are YAML with the same fields
+		content, err = config.YAMLToJSON(path)
+	default:
+		content, err = config.Read(path, a.offline)
+	}
+	if err != nil {
+		return withKind(kindIO, err)
+	}
+
+	baseDir := filepath.Dir(path)
+	if config.IsRemote(path) {
+		baseDir = "."
+	}
+
+	issues := validateConfig(content, baseDir)
+	if len(issues) == 0 {
+		a.logger.Success("%s is valid", path)
+		return nil
+	}
+
+	for _, issue := range issues {
+		a.logger.Error("%s: %s", issue.path, issue.message)
+	}
+	if len(issues) == 1 {
+		return withKind(kindConfigInvalid, fmt.Errorf("%s has 1 issue", path))
+	}
+	return withKind(kindConfigInvalid, fmt.Errorf("%s has %d issues", path, len(issues)))
+}
+
+// validateConfig checks the content of a configuration file. Relative paths
+// in it are resolved against baseDir.
+func validateConfig(content []byte, baseDir string) []configIssue {
+	var raw any
+	if err := json.Unmarshal(content, &raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, column := position(content, syntaxErr.Offset)
+			return []configIssue{{"$", fmt.Sprintf("invalid JSON at line %d, column %d: %v", line, column, err)}}
+		}
+		return []configIssue{{"$", err.Error()}}
+	}
+
+	issues := unknownFields(raw, reflect.TypeOf(config.Config{}), "$")
+
+	var cfg config.Config
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return append(issues, configIssue{"$." + typeErr.Field, fmt.Sprintf("expected %s, got %s", typeErr.Type, typeErr.Value)})
+		}
+		return append(issues, configIssue{"$", err.Error()})
+	}
+
+	return append(issues, invalidValues(&cfg, baseDir)...)
+}
+
+// unknownFields reports the object keys in value that have no field in t
+func unknownFields(value any, t reflect.Type, path string) []configIssue {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return unknownFields(value, t.Elem(), path)
+	case reflect.Slice:
+		var issues []configIssue
+		if items, ok := value.([]any); ok {
+			for i, item := range items {
+				issues = append(issues, unknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+		return issues
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var issues []configIssue
+	for _, key := range keys {
+		fieldType, ok := fields[key]
+		if !ok {
+			issues = append(issues, configIssue{path + "." + key, "unknown field"})
+			continue
+		}
+		issues = append(issues, unknownFields(object[key], fieldType, path+"."+key)...)
+	}
+	return issues
+}
+
+// invalidValues reports options that would make generation fail or that have
+// no effect
+func invalidValues(cfg *config.Config, baseDir string) []configIssue {
+	var issues []configIssue
+	add := func(path string, err error) {
+		if err != nil {
+			issues = append(issues, configIssue{path, err.Error()})
+		}
+	}
+
+	if cfg.Mode != "" && cfg.Mode != generator.ModeImplementations && cfg.Mode != generator.ModeMocks {
+		add("$.mode", fmt.Errorf("unknown mode %q (expected %s or %s)", cfg.Mode, generator.ModeImplementations, generator.ModeMocks))
+	}
+	if cfg.Layout != "" && cfg.Layout != generator.LayoutFlat && cfg.Layout != generator.LayoutLayered {
+		add("$.layout", fmt.Errorf("unknown layout %q (expected %s or %s)", cfg.Layout, generator.LayoutFlat, generator.LayoutLayered))
+	}
+	_, err := lineSeparator(cfg.LineEndings)
+	add("$.line_endings", err)
+
+	for i, pattern := range cfg.Include {
+		_, err := compilePatterns([]string{pattern})
+		add(fmt.Sprintf("$.include[%d]", i), err)
+	}
+	for i, pattern := range cfg.Exclude {
+		_, err := compilePatterns([]string{pattern})
+		add(fmt.Sprintf("$.exclude[%d]", i), err)
+	}
+	for i, layer := range cfg.Layers {
+		_, err := newInterfaceFilter(nil, nil, []string{layer})
+		add(fmt.Sprintf("$.layers[%d]", i), err)
+	}
+	issues = append(issues, duplicates("$.include", cfg.Include)...)
+	issues = append(issues, duplicates("$.exclude", cfg.Exclude)...)
+	issues = append(issues, duplicates("$.layers", cfg.Layers)...)
+
+	keys := make([]string, 0, len(cfg.LayerDirs))
+	for key := range cfg.LayerDirs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		path := "$.layer_dirs." + key
+		if _, ok := generator.DefaultLayerDirs[key]; !ok {
+			add(path, fmt.Errorf("unknown layer %q (expected one of %s, di)", key, strings.Join(layers, ", ")))
+		} else if dir := cfg.LayerDirs[key]; dir != "" && !filepath.IsLocal(dir) {
+			add(path, fmt.Errorf("directory %q must be relative to the output directory", dir))
+		}
+	}
+
+	style := generator.Style{
+		Receiver:       cfg.Style.Receiver,
+		Constructor:    cfg.Style.Constructor,
+		ValueReceivers: cfg.Style.ValueReceivers,
+		ErrorWrapping:  cfg.Style.ErrorWrapping,
+		Comments:       cfg.Style.Comments,
+	}
+	add("$.style", style.Validate())
+
+	_, err = licenseHeader(cfg, baseDir)
+	add("$.license", err)
+
+	for i, command := range cfg.Hooks.PreGenerate {
+		if strings.TrimSpace(command) == "" {
+			add(fmt.Sprintf("$.hooks.pre_generate[%d]", i), errors.New("empty command"))
+		}
+	}
+	for i, command := range cfg.Hooks.PostGenerate {
+		if strings.TrimSpace(command) == "" {
+			add(fmt.Sprintf("$.hooks.post_generate[%d]", i), errors.New("empty command"))
+		}
+	}
+	for i, answer := range cfg.Prompts.Yes {
+		if strings.TrimSpace(answer) == "" {
+			add(fmt.Sprintf("$.prompts.yes[%d]", i), errors.New("empty answer"))
+		}
+	}
+
+	return issues
+}
+
+// duplicates reports list entries repeating an earlier one
+func duplicates(path string, values []string) []configIssue {
+	var issues []configIssue
+	seen := make(map[string]int)
+	for i, value := range values {
+		key := strings.TrimSpace(value)
+		if first, ok := seen[key]; ok {
+			issues = append(issues, configIssue{fmt.Sprintf("%s[%d]", path, i), fmt.Sprintf("duplicate of %s[%d]", path, first)})
+			continue
+		}
+		seen[key] = i
+	}
+	return issues
+}
+
+// position returns the 1-based line and column of a byte offset
+func position(content []byte, offset int64) (line, column int) {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	before := content[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
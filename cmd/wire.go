@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/navyarakshakarya/code-gen/generator"
+	"github.com/navyarakshakarya/code-gen/logger"
+)
+
+// checkWire runs `wire check` on the package of the generated injectors when
+// the wire tool is installed. Failures are reported as warnings, since they
+// usually mean the project still lacks dependencies such as go.mod entries.
+func checkWire(results []*generator.GeneratedFile, outputDir string, offline bool, logger *logger.Logger) {
+	wirePath, err := exec.LookPath("wire")
+	if err != nil {
+		logger.Info("Install wire to validate the injectors: go install github.com/google/wire/cmd/wire@latest")
+		return
+	}
+
+	for _, result := range results {
+		if path.Base(result.Filename) != generator.WireFile {
+			continue
+		}
+
+		pkg := "./" + path.Dir(result.Filename)
+		cmd := exec.Command(wirePath, "check", pkg)
+		cmd.Dir = outputDir
+		if offline {
+			cmd.Env = append(os.Environ(), offlineEnv...)
+		}
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			logger.Warning("wire check failed for %s:\n%s", filepath.FromSlash(pkg), strings.TrimSpace(string(output)))
+			continue
+		}
+		logger.Success("wire check passed: %s", filepath.FromSlash(pkg))
+	}
+}
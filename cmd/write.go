@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/navyarakshakarya/code-gen/generator"
+	"github.com/navyarakshakarya/code-gen/logger"
+)
+
+// Suffixes of the temporary files used while writing generated files
+const (
+	stagingSuffix = ".code-gen-tmp"
+	backupSuffix  = ".code-gen-bak"
+)
+
+// fileWrite is a generated file being moved into place
+type fileWrite struct {
+	path      string
+	staged    bool // the content was written to path + stagingSuffix
+	backedUp  bool // the previous file was moved to path + backupSuffix
+	committed bool // the staged file was moved to path
+}
+
+// writeFiles writes the generated files according to their planned status.
+// All files are staged next to their destination first and then moved into
+// place; if any step fails, replaced files are restored from their backups and
+// new files and directories are removed, leaving outputDir as it was.
+func writeFiles(results []*generator.GeneratedFile, outputDir string, statuses map[string]fileStatus, logger *logger.Logger) (written []string, skipped int, err error) {
+	var writes []*fileWrite
+	var createdDirs []string
+
+	rollback := func() {
+		for i := len(writes) - 1; i >= 0; i-- {
+			w := writes[i]
+			if w.committed {
+				os.Remove(w.path)
+			}
+			if w.backedUp {
+				if err := os.Rename(w.path+backupSuffix, w.path); err != nil {
+					logger.Error("Failed to restore %s: %v", w.path, err)
+				}
+			}
+			if w.staged && !w.committed {
+				os.Remove(w.path + stagingSuffix)
+			}
+		}
+		for i := len(createdDirs) - 1; i >= 0; i-- {
+			os.Remove(createdDirs[i])
+		}
+	}
+
+	// Stage every file
+	for _, result := range results {
+		switch statuses[result.Filename] {
+		case statusUpToDate:
+			logger.Info("Up to date: %s", result.Filename)
+			continue
+		case statusKept:
+			logger.Info("Kept scaffold: %s", result.Filename)
+			continue
+		case statusSkip:
+			logger.Warning("File exists, skipping: %s", result.Filename)
+			skipped++
+			continue
+		}
+
+		filePath := filepath.Join(outputDir, result.Filename)
+		dirs, err := mkdirAll(filepath.Dir(filePath))
+		createdDirs = append(createdDirs, dirs...)
+		if err != nil {
+			rollback()
+			return nil, skipped, fmt.Errorf("failed to create directory for %s: %w", result.Filename, err)
+		}
+
+		w := &fileWrite{path: filePath}
+		writes = append(writes, w)
+		if err := os.WriteFile(filePath+stagingSuffix, []byte(result.Content), 0644); err != nil {
+			rollback()
+			return nil, skipped, fmt.Errorf("failed to write %s: %w", result.Filename, err)
+		}
+		w.staged = true
+	}
+
+	// Move staged files into place, keeping backups of replaced files
+	for _, w := range writes {
+		if _, err := os.Stat(w.path); err == nil {
+			if err := os.Rename(w.path, w.path+backupSuffix); err != nil {
+				rollback()
+				return nil, skipped, fmt.Errorf("failed to back up %s: %w", w.path, err)
+			}
+			w.backedUp = true
+		}
+
+		if err := os.Rename(w.path+stagingSuffix, w.path); err != nil {
+			rollback()
+			return nil, skipped, fmt.Errorf("failed to write %s: %w", w.path, err)
+		}
+		w.committed = true
+	}
+
+	for _, w := range writes {
+		if w.backedUp {
+			os.Remove(w.path + backupSuffix)
+		}
+	}
+
+	for _, result := range results {
+		if status := statuses[result.Filename]; status == statusNew || status == statusOverwrite {
+			logger.Success("Generated: %s", result.Filename)
+			written = append(written, result.Filename)
+		}
+	}
+
+	return written, skipped, nil
+}
+
+// mkdirAll creates dir and any missing parents, returning the directories it
+// created from the outermost to the innermost
+func mkdirAll(dir string) ([]string, error) {
+	var missing []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		missing = append([]string{d}, missing...)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	for i, d := range missing {
+		if err := os.Mkdir(d, 0755); err != nil && !errors.Is(err, os.ErrExist) {
+			return missing[:i], err
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/navyarakshakarya/code-gen/generator"
+	"github.com/navyarakshakarya/code-gen/logger"
+)
+
+// listFiles returns the slash-separated paths of all files and directories in dir
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, _ os.DirEntry, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		paths = append(paths, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "update.go"), "old")
+	writeFile(t, filepath.Join(dir, "skip.go"), "edited")
+	writeFile(t, filepath.Join(dir, "same.go"), "same")
+
+	results := []*generator.GeneratedFile{
+		{Filename: "update.go", Content: "new"},
+		{Filename: filepath.Join("sub", "create.go"), Content: "created"},
+		{Filename: "skip.go", Content: "generated"},
+		{Filename: "same.go", Content: "same"},
+	}
+	statuses := map[string]fileStatus{
+		"update.go":                       statusOverwrite,
+		filepath.Join("sub", "create.go"): statusNew,
+		"skip.go":                         statusSkip,
+		"same.go":                         statusUpToDate,
+	}
+
+	written, skipped, err := writeFiles(results, dir, statuses, logger.New(false, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"update.go", filepath.Join("sub", "create.go")}; !reflect.DeepEqual(written, want) {
+		t.Errorf("written = %q, want %q", written, want)
+	}
+	if skipped != 1 {
+		t.Errorf("skipped = %d, want 1", skipped)
+	}
+	for name, want := range map[string]string{"update.go": "new", "sub/create.go": "created", "skip.go": "edited", "same.go": "same"} {
+		if got := readFile(t, filepath.Join(dir, filepath.FromSlash(name))); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if want := []string{"same.go", "skip.go", "sub", "sub/create.go", "update.go"}; !reflect.DeepEqual(listFiles(t, dir), want) {
+		t.Errorf("files = %q, want %q: staging files or backups were left behind", listFiles(t, dir), want)
+	}
+}
+
+func TestWriteFilesRollsBackFailedRename(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "first.go"), "old first")
+	writeFile(t, filepath.Join(dir, "last.go"), "old last")
+	// A directory in place of the backup makes backing up last.go fail
+	// after the other files were moved into place
+	writeFile(t, filepath.Join(dir, "last.go"+backupSuffix, "keep"), "")
+	before := listFiles(t, dir)
+
+	results := []*generator.GeneratedFile{
+		{Filename: "first.go", Content: "new first"},
+		{Filename: "created.go", Content: "created"},
+		{Filename: filepath.Join("new", "dir", "nested.go"), Content: "nested"},
+		{Filename: "last.go", Content: "new last"},
+	}
+	statuses := map[string]fileStatus{
+		"first.go":                               statusOverwrite,
+		"created.go":                             statusNew,
+		filepath.Join("new", "dir", "nested.go"): statusNew,
+		"last.go":                                statusOverwrite,
+	}
+
+	written, _, err := writeFiles(results, dir, statuses, logger.New(false, true))
+	if err == nil {
+		t.Fatal("writeFiles() succeeded, want the failed backup of last.go")
+	}
+	if written != nil {
+		t.Errorf("written = %q after a failure, want none", written)
+	}
+
+	if after := listFiles(t, dir); !reflect.DeepEqual(after, before) {
+		t.Errorf("files = %q after the rollback, want %q", after, before)
+	}
+	for name, want := range map[string]string{"first.go": "old first", "last.go": "old last"} {
+		if got := readFile(t, filepath.Join(dir, name)); got != want {
+			t.Errorf("%s = %q after the rollback, want %q", name, got, want)
+		}
+	}
+}
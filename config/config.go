@@ -0,0 +1,124 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config holds generation options loaded from a JSON configuration file.
+// Command-line flags take precedence over values set here.
+type Config struct {
+	Output string   `json:"output,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	Force  bool     `json:"force,omitempty"`
+	Mode   string   `json:"mode,omitempty"`
+
+	// Offline keeps generation off the network, as --offline does
+	Offline bool `json:"offline,omitempty"`
+
+	// Tests and Bench generate test and benchmark scaffolds, as --tests and
+	// --bench do
+	Tests bool `json:"tests,omitempty"`
+	Bench bool `json:"bench,omitempty"`
+
+	// Include and Exclude select the interfaces to generate code for by name
+	// (or package.Name): glob patterns, or regular expressions when enclosed
+	// in slashes. Layers restricts generation to the listed layers.
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	Layers  []string `json:"layers,omitempty"`
+
+	// Skip replaces the analyzer's skip list (directories end in "/", other
+	// entries are file globs); Gitignore also skips files ignored by git;
+	// AnalyzeTests includes _test.go files when generating mocks
+	Skip         []string `json:"skip,omitempty"`
+	Gitignore    bool     `json:"gitignore,omitempty"`
+	AnalyzeTests bool     `json:"analyze_tests,omitempty"`
+
+	// LineEndings of written files: "lf" (default), "crlf" or "native"
+	// (crlf on Windows, lf elsewhere)
+	LineEndings string `json:"line_endings,omitempty"`
+
+	// Layout is "flat" (default) or "layered"; LayerDirs overrides the
+	// package directory per layer ("repository", "usecase", "handler",
+	// "service") and for the factory and wire files ("di")
+	Layout    string            `json:"layout,omitempty"`
+	LayerDirs map[string]string `json:"layer_dirs,omitempty"`
+
+	// License is an SPDX identifier or the path of a header file prepended
+	// to every generated file; Copyright names the holder for SPDX headers
+	License   string `json:"license,omitempty"`
+	Copyright string `json:"copyright,omitempty"`
+
+	// Style of the generated implementations
+	Style Style `json:"style,omitempty"`
+
+	// Prompts replaces the interactive prompt strings, e.g. to localize them
+	Prompts Prompts `json:"prompts,omitempty"`
+
+	Hooks Hooks `json:"hooks,omitempty"`
+
+	// Stats is the path of a local JSON Lines file a record of every run is
+	// appended to: durations, file counts and failures. Unset disables stats.
+	Stats string `json:"stats,omitempty"`
+}
+
+// Prompts are the strings of interactive prompts; empty values keep the
+// English defaults
+type Prompts struct {
+	ConfirmWrite string   `json:"confirm_write,omitempty"` // asked before writing files
+	Choices      string   `json:"choices,omitempty"`       // appended to questions, e.g. "[y/N]"
+	Yes          []string `json:"yes,omitempty"`           // answers accepted as yes
+}
+
+// Style controls the naming and formatting of generated implementations
+type Style struct {
+	Receiver       string `json:"receiver,omitempty"`        // "impl" (default), "short" or any identifier
+	Constructor    string `json:"constructor,omitempty"`     // "interface" for New<Interface> (default) or "short" for New
+	ValueReceivers bool   `json:"value_receivers,omitempty"` // value instead of pointer receivers
+	ErrorWrapping  string `json:"error_wrapping,omitempty"`  // "fmt" for fmt.Errorf (default) or "join" for errors.Join
+	Comments       string `json:"comments,omitempty"`        // "full" (default), "minimal" or "none"
+}
+
+// Hooks are shell commands run in the output directory around generation
+type Hooks struct {
+	PreGenerate  []string `json:"pre_generate,omitempty"`  // before the project is analyzed
+	PostGenerate []string `json:"post_generate,omitempty"` // after files were written
+}
+
+// Load reads and parses the configuration at path, which may also be a
+// remote source (see IsRemote) unless offline
+func Load(path string, offline bool) (*Config, error) {
+	content, err := Read(path, offline)
+	if err != nil {
+		return nil, err
+	}
+
+	return parse(path, content)
+}
+
+// parse decodes the JSON configuration read from path
+func parse(path string, content []byte) (*Config, error) {
+	var cfg Config
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Read returns the content of the configuration at path, fetching remote
+// sources unless offline
+func Read(path string, offline bool) ([]byte, error) {
+	var content []byte
+	var err error
+	if IsRemote(path) {
+		content, err = fetch(path, offline)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	return content, nil
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultsPath returns the location of the organization defaults file,
+// codegen/defaults.yaml in the user configuration directory
+// (~/.config/codegen/defaults.yaml on Linux)
+func DefaultsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "codegen", "defaults.yaml"), nil
+}
+
+// LoadWithDefaults reads the configuration at path merged over the defaults
+// file at defaultsPath. Either may be empty, and a missing defaults file is
+// ignored. Values set in the configuration win; objects such as style are
+// merged field by field. Remote configurations are refused when offline.
+func LoadWithDefaults(path, defaultsPath string, offline bool) (*Config, error) {
+	merged := make(map[string]any)
+
+	if defaultsPath != "" {
+		defaults, err := readDefaults(defaultsPath)
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeObjects(merged, defaults)
+	}
+
+	if path != "" {
+		content, err := Read(path, offline)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := parse(path, content); err != nil {
+			return nil, err
+		}
+		var project map[string]any
+		if err := json.Unmarshal(content, &project); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+		merged = mergeObjects(merged, project)
+	}
+
+	content, err := json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge config: %w", err)
+	}
+	return parse(path, content)
+}
+
+// readDefaults reads the YAML defaults file as a JSON object, returning nil
+// when it does not exist. A license naming a file is made absolute, since
+// it is relative to the defaults file rather than the project.
+func readDefaults(path string) (map[string]any, error) {
+	content, err := YAMLToJSON(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parse(path, content); err != nil {
+		return nil, err
+	}
+
+	var defaults map[string]any
+	if err := json.Unmarshal(content, &defaults); err != nil {
+		return nil, fmt.Errorf("failed to parse defaults %s: %w", path, err)
+	}
+
+	if license, ok := defaults["license"].(string); ok && license != "" && !filepath.IsAbs(license) {
+		file := filepath.Join(filepath.Dir(path), license)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			if abs, err := filepath.Abs(file); err == nil {
+				defaults["license"] = abs
+			}
+		}
+	}
+	return defaults, nil
+}
+
+// YAMLToJSON reads a YAML file using the keys of the JSON configuration and
+// returns it as JSON
+func YAMLToJSON(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	if err := yaml.Unmarshal(content, &value); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if value == nil {
+		value = map[string]any{}
+	}
+	if _, ok := value.(map[string]any); !ok {
+		return nil, fmt.Errorf("failed to parse %s: expected a mapping of options", path)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// mergeObjects returns base with the values of override set over it,
+// merging nested objects
+func mergeObjects(base, override map[string]any) map[string]any {
+	for key, value := range override {
+		nested, ok := value.(map[string]any)
+		if existing, isObject := base[key].(map[string]any); ok && isObject {
+			base[key] = mergeObjects(existing, nested)
+			continue
+		}
+		base[key] = value
+	}
+	return base
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// object decodes a JSON object
+func object(t *testing.T, data string) map[string]any {
+	t.Helper()
+
+	var value map[string]any
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestMergeObjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		override string
+		want     string
+	}{
+		{"empty override", `{"force": true}`, `{}`, `{"force": true}`},
+		{"empty base", `{}`, `{"force": true}`, `{"force": true}`},
+		{"disjoint keys", `{"force": true}`, `{"output": "gen"}`, `{"force": true, "output": "gen"}`},
+		{"override wins", `{"output": "a", "force": true}`, `{"output": "b"}`, `{"output": "b", "force": true}`},
+		{"false overrides true", `{"force": true}`, `{"force": false}`, `{"force": false}`},
+		{"null overrides", `{"output": "a"}`, `{"output": null}`, `{"output": null}`},
+		{
+			"nested objects are merged",
+			`{"style": {"receiver": "short", "comments": "minimal"}}`,
+			`{"style": {"receiver": "impl"}}`,
+			`{"style": {"receiver": "impl", "comments": "minimal"}}`,
+		},
+		{
+			"deeply nested objects are merged",
+			`{"a": {"b": {"c": 1, "d": 2}}}`,
+			`{"a": {"b": {"d": 3}}}`,
+			`{"a": {"b": {"c": 1, "d": 3}}}`,
+		},
+		{"arrays are replaced", `{"tags": ["a", "b"]}`, `{"tags": ["c"]}`, `{"tags": ["c"]}`},
+		{"object replaces scalar", `{"style": "none"}`, `{"style": {"receiver": "impl"}}`, `{"style": {"receiver": "impl"}}`},
+		{"scalar replaces object", `{"style": {"receiver": "impl"}}`, `{"style": "none"}`, `{"style": "none"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeObjects(object(t, tt.base), object(t, tt.override))
+			if want := object(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("mergeObjects(%s, %s) = %v, want %v", tt.base, tt.override, got, want)
+			}
+		})
+	}
+}
+
+func TestLoadWithDefaults(t *testing.T) {
+	dir := t.TempDir()
+	defaultsPath := filepath.Join(dir, "defaults.yaml")
+	if err := os.WriteFile(defaultsPath, []byte("tests: true\nbench: true\nlayout: layered\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "code-gen.json")
+	if err := os.WriteFile(path, []byte(`{"bench": false, "force": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadWithDefaults(path, defaultsPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Tests || cfg.Bench || !cfg.Force || cfg.Layout != "layered" {
+		t.Errorf("LoadWithDefaults() = tests %v, bench %v, force %v, layout %q, want true, false, true, layered", cfg.Tests, cfg.Bench, cfg.Force, cfg.Layout)
+	}
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitPrefix marks a configuration read from a git repository
+const gitPrefix = "git::"
+
+// fetchTimeout bounds downloading a remote configuration
+const fetchTimeout = 60 * time.Second
+
+// maxRemoteSize bounds the size of a configuration downloaded over HTTP(S)
+const maxRemoteSize = 1 << 20
+
+// ErrOffline is returned for remote configurations read in offline mode
+var ErrOffline = errors.New("remote configurations cannot be fetched offline; use a local copy or drop --offline")
+
+// IsRemote reports whether source names a configuration fetched over HTTP(S)
+// or from a git repository rather than a local file
+func IsRemote(source string) bool {
+	return strings.HasPrefix(source, gitPrefix) ||
+		strings.HasPrefix(source, "https://") ||
+		strings.HasPrefix(source, "http://")
+}
+
+// fetch reads a remote configuration. Sources are HTTP(S) URLs or
+// git::<repository>//<file>?ref=<ref>, where a single slash names a file at the
+// root of the repository, and may pin the content with a
+// checksum=sha256:<hex> query parameter. Offline, nothing is fetched.
+func fetch(source string, offline bool) ([]byte, error) {
+	if offline {
+		return nil, ErrOffline
+	}
+
+	location, query, err := splitQuery(source)
+	if err != nil {
+		return nil, err
+	}
+	checksum := query.Get("checksum")
+	query.Del("checksum")
+
+	var content []byte
+	if strings.HasPrefix(location, gitPrefix) {
+		content, err = fetchGit(strings.TrimPrefix(location, gitPrefix), query.Get("ref"))
+	} else {
+		if len(query) > 0 {
+			location += "?" + query.Encode()
+		}
+		content, err = fetchHTTP(location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if checksum != "" {
+		if err := verifyChecksum(content, checksum); err != nil {
+			return nil, err
+		}
+	}
+	return content, nil
+}
+
+// splitQuery separates the query parameters from a source. It does not parse
+// the source as a URL, since git sources may use scp-like SSH addresses.
+func splitQuery(source string) (string, url.Values, error) {
+	location, rawQuery, _ := strings.Cut(source, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid query in %s: %w", source, err)
+	}
+	return location, query, nil
+}
+
+// fetchHTTP downloads the configuration at rawURL
+func fetchHTTP(rawURL string) ([]byte, error) {
+	client := &http.Client{Timeout: fetchTimeout}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", rawURL, resp.Status)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	if len(content) > maxRemoteSize {
+		return nil, fmt.Errorf("failed to fetch %s: larger than %d bytes", rawURL, maxRemoteSize)
+	}
+	return content, nil
+}
+
+// fetchGit reads a file from a git repository at ref (default: the remote
+// HEAD) with a shallow fetch into a temporary repository. The file is named
+// after a double slash, https://example.com/org/repo.git//path/config.json, or
+// is the last path element of the source.
+func fetchGit(source, ref string) ([]byte, error) {
+	repository, file, err := splitGitSource(source)
+	if err != nil {
+		return nil, err
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+	// Refs are passed to git fetch, which must not take them for options
+	if strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid ref %q in %s", ref, source)
+	}
+
+	dir, err := os.MkdirTemp("", "code-gen-config-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", source, err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	git := func(args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		output, err := cmd.Output()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return output, err
+	}
+
+	if _, err := git("init", "--quiet"); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", source, err)
+	}
+	if _, err := git("fetch", "--quiet", "--depth", "1", "--", repository, ref); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s at %s: %w", repository, ref, err)
+	}
+	content, err := git("show", "FETCH_HEAD:"+file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from %s at %s: %w", file, repository, ref, err)
+	}
+	return content, nil
+}
+
+// splitGitSource splits repository//file into the repository and the file.
+// Without a double slash, the last path element names a file at the root of
+// the repository: https://example.com/org/repo/config.json.
+func splitGitSource(source string) (repository, file string, err error) {
+	start := 0
+	if i := strings.Index(source, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	if i := strings.Index(source[start:], "//"); i >= 0 {
+		repository, file = source[:start+i], source[start+i+2:]
+	} else if i := strings.LastIndex(source[start:], "/"); i >= 0 && !strings.HasSuffix(source, ".git") {
+		repository, file = source[:start+i], source[start+i+1:]
+	}
+	if strings.TrimSpace(file) == "" || strings.TrimSpace(repository[start:]) == "" {
+		return "", "", fmt.Errorf("git config source %s must name the file, e.g. git::https://example.com/org/repo.git//path/code-gen.json, or git::https://example.com/org/repo/code-gen.json for a file at the root", source)
+	}
+	return repository, file, nil
+}
+
+// verifyChecksum compares the SHA-256 of content to a sha256:<hex> checksum
+func verifyChecksum(content []byte, checksum string) error {
+	algorithm, want, ok := strings.Cut(checksum, ":")
+	if !ok {
+		algorithm, want = "sha256", checksum
+	}
+	if algorithm != "sha256" {
+		return fmt.Errorf("unsupported checksum %s, use sha256:<hex>", checksum)
+	}
+
+	sum := sha256.Sum256(content)
+	if got := hex.EncodeToString(sum[:]); got != strings.ToLower(want) {
+		return fmt.Errorf("config checksum mismatch: got sha256:%s, want sha256:%s", got, want)
+	}
+	return nil
+}
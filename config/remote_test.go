@@ -0,0 +1,174 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	content := []byte("{}\n")
+	// sha256 of "{}\n"
+	const sum = "ca3d163bab055381827226140568f3bef7eaac187cebd76878e0b63e9e442356"
+
+	tests := []struct {
+		name     string
+		checksum string
+		wantErr  string
+	}{
+		{"sha256", "sha256:" + sum, ""},
+		{"upper case hex", "sha256:" + strings.ToUpper(sum), ""},
+		{"without algorithm", sum, ""},
+		{"mismatch", "sha256:" + strings.Repeat("0", 64), "checksum mismatch"},
+		{"truncated", "sha256:" + sum[:10], "checksum mismatch"},
+		{"empty hex", "sha256:", "checksum mismatch"},
+		{"other algorithm", "md5:" + sum, "unsupported checksum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyChecksum(content, tt.checksum)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("verifyChecksum(%q) = %v, want no error", tt.checksum, err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("verifyChecksum(%q) = %v, want an error containing %q", tt.checksum, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetchHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/code-gen.json":
+			w.Write([]byte("{}\n"))
+		case "/large.json":
+			w.Write([]byte(strings.Repeat(" ", maxRemoteSize+1)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"found", server.URL + "/code-gen.json", ""},
+		{"pinned", server.URL + "/code-gen.json?checksum=sha256:ca3d163bab055381827226140568f3bef7eaac187cebd76878e0b63e9e442356", ""},
+		{"changed", server.URL + "/code-gen.json?checksum=sha256:" + strings.Repeat("0", 64), "checksum mismatch"},
+		{"not found", server.URL + "/missing.json", "404"},
+		{"too large", server.URL + "/large.json", "larger than"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := fetch(tt.source, false)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("fetch(%s) = %v, want no error", tt.source, err)
+			case tt.wantErr == "" && string(content) != "{}\n":
+				t.Errorf("fetch(%s) = %q, want {}", tt.source, content)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("fetch(%s) = %v, want an error containing %q", tt.source, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetchOffline(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	for _, source := range []string{server.URL + "/code-gen.json", "git::" + server.URL + "/repo.git//code-gen.json"} {
+		if _, err := fetch(source, true); !errors.Is(err, ErrOffline) {
+			t.Errorf("fetch(%s) offline = %v, want ErrOffline", source, err)
+		}
+	}
+	if requested {
+		t.Error("fetching offline reached the server")
+	}
+}
+
+func TestFetchGitRejectsOptionRefs(t *testing.T) {
+	_, err := fetch("git::https://example.com/repo.git//code-gen.json?ref=--upload-pack=touch%20pwned", false)
+	if err == nil || !strings.Contains(err.Error(), "invalid ref") {
+		t.Errorf("fetch() = %v, want an invalid ref error", err)
+	}
+}
+
+func TestSplitGitSource(t *testing.T) {
+	tests := []struct {
+		source     string
+		repository string
+		file       string
+		wantErr    bool
+	}{
+		{"https://github.com/acme/golden.git//go/code-gen.json", "https://github.com/acme/golden.git", "go/code-gen.json", false},
+		{"git@github.com:acme/golden.git//code-gen.json", "git@github.com:acme/golden.git", "code-gen.json", false},
+		// Without a double slash the last path element is the file
+		{"https://github.com/acme/golden/cta.json", "https://github.com/acme/golden", "cta.json", false},
+		{"git@github.com:acme/golden/cta.json", "git@github.com:acme/golden", "cta.json", false},
+		{"https://github.com/acme/golden.git", "", "", true},
+		{"https://github.com/acme/golden.git//", "", "", true},
+		{"https://github.com/acme/golden/", "", "", true},
+		{"https://github.com", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			repository, file, err := splitGitSource(tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitGitSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if repository != tt.repository || file != tt.file {
+				t.Errorf("splitGitSource() = %q, %q, want %q, %q", repository, file, tt.repository, tt.file)
+			}
+		})
+	}
+}
+
+func TestFetchGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "go"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"cta.json", "go/code-gen.json"} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte("{}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "--quiet", "--initial-branch", "main")
+	git("add", ".")
+	git("-c", "user.name=code-gen", "-c", "user.email=code-gen@example.com", "commit", "--quiet", "-m", "config")
+
+	repository := "file://" + filepath.ToSlash(dir)
+	for _, source := range []string{
+		"git::" + repository + "//go/code-gen.json?ref=main",
+		"git::" + repository + "/cta.json?ref=main",
+		"git::" + repository + "/cta.json",
+	} {
+		if content, err := fetch(source, false); err != nil || string(content) != "{}\n" {
+			t.Errorf("fetch(%s) = %q, %v, want {}", source, content, err)
+		}
+	}
+}
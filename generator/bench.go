@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// generateBenchmark generates a benchmark per method of a repository or use
+// case, as a scaffold the project completes. Repositories are measured through
+// their in-memory implementation, and use cases are built on the in-memory
+// implementations of their repositories, returned in memory.
+func (g *Generator) generateBenchmark(key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) (file *GeneratedFile, memory []string) {
+	target := g.implPackage(interfaceInfo, projectInfo)
+	source := g.sourcePackage(interfaceInfo, projectInfo)
+	testPkg := g.testPackage(target)
+	prefix := qualifier(source, testPkg)
+	interfaceName := interfaceInfo.Name
+
+	fileName := path.Join(target.dir, strings.TrimSuffix(g.generateFileName(interfaceName, interfaceInfo.Layer), ".gen.go")+"_bench_test.go")
+
+	imports := map[string]bool{"testing": true}
+
+	// memoryRef returns the constructor of the in-memory implementation of
+	// the repository with the given key, adding the import it needs
+	memoryRef := func(repoKey string) string {
+		memory = append(memory, repoKey)
+		memoryPkg, memoryName, _ := g.testDouble(repoKey, projectInfo.Interfaces[repoKey], projectInfo, "Memory")
+		if memoryPkg.importPath != testPkg.importPath {
+			imports[memoryPkg.importPath] = true
+		}
+		return qualifier(memoryPkg, testPkg) + "New" + memoryName + "()"
+	}
+
+	// setup declares impl, the implementation measured
+	var setup strings.Builder
+	if interfaceInfo.Layer == types.RepositoryLayer {
+		setup.WriteString(fmt.Sprintf("\t// TODO: Measure %s with a database instead\n", g.constructorName(interfaceName)))
+		setup.WriteString(fmt.Sprintf("\tvar impl %s = %s\n", qualifyType(interfaceName, prefix), memoryRef(key)))
+		if prefix != "" {
+			imports[source.importPath] = true
+		}
+	} else {
+		var args []string
+		for _, dep := range g.generateDependencies(key, interfaceInfo, projectInfo) {
+			parts := strings.Fields(dep)
+			if len(parts) < 2 {
+				continue
+			}
+			depKey, exists := g.analyzedInterface(parts[1], typeScope{dir: source.dir}, projectInfo)
+			if !exists || projectInfo.Interfaces[depKey].Layer != types.RepositoryLayer {
+				args = append(args, "nil")
+				continue
+			}
+			setup.WriteString(fmt.Sprintf("\t%s := %s\n", parts[0], memoryRef(depKey)))
+			setup.WriteString(fmt.Sprintf("\t// TODO: Store representative entities in %s\n", parts[0]))
+			args = append(args, parts[0])
+		}
+		if target.importPath != testPkg.importPath {
+			imports[target.importPath] = true
+		}
+		setup.WriteString(fmt.Sprintf("\timpl := %s%s(%s)\n", qualifier(target, testPkg), g.constructorName(interfaceName), strings.Join(args, ", ")))
+	}
+
+	var body strings.Builder
+
+	for _, method := range interfaceInfo.Methods {
+		var args []string
+		methodContext := false
+		for _, param := range method.Params {
+			if param.Type == "context.Context" {
+				args = append(args, "ctx")
+				methodContext = true
+				continue
+			}
+			arg := g.testArgument(param.Type, prefix, interfaceInfo, imports, projectInfo)
+			if arg == "" {
+				// Variadic parameters are called with no arguments
+				continue
+			}
+			args = append(args, arg)
+			g.addArgumentImports(arg, prefix, source, interfaceInfo, imports, projectInfo)
+		}
+		if methodContext {
+			imports["context"] = true
+		}
+
+		body.WriteString(fmt.Sprintf("// Benchmark%s_%s measures %s.%s\n", interfaceName, method.Name, interfaceName, method.Name))
+		body.WriteString(fmt.Sprintf("func Benchmark%s_%s(b *testing.B) {\n", interfaceName, method.Name))
+		body.WriteString(setup.String())
+		if methodContext {
+			body.WriteString("\tctx := context.Background()\n")
+		}
+		body.WriteString("\t// TODO: Set up representative inputs\n\n")
+		body.WriteString("\tb.ResetTimer()\n")
+		body.WriteString("\tfor i := 0; i < b.N; i++ {\n")
+		body.WriteString(fmt.Sprintf("\t\timpl.%s(%s)\n", method.Name, strings.Join(args, ", ")))
+		body.WriteString("\t}\n")
+		body.WriteString("}\n\n")
+	}
+
+	var content strings.Builder
+
+	g.writeScaffoldHeader(&content, testPkg.name)
+
+	content.WriteString("import (\n")
+	for _, imp := range importSpecs(imports) {
+		content.WriteString(fmt.Sprintf("\t%s\n", imp))
+	}
+	content.WriteString(")\n\n")
+
+	content.WriteString(body.String())
+
+	return &GeneratedFile{
+		Filename:  fileName,
+		Content:   content.String(),
+		LineCount: strings.Count(content.String(), "\n"),
+		Scaffold:  true,
+	}, memory
+}
@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateBenchmark(t *testing.T) {
+	files := generate(t, Options{Benchmarks: true}, analyze(t, map[string]string{"shop.go": shopSource}))
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"user_repository_bench_test.go", []string{
+			"// Scaffolded by code-gen. Edit freely: code-gen does not overwrite this file.\n\npackage shop_test\n",
+			"func BenchmarkUserRepository_GetByID(b *testing.B) {\n\t// TODO: Measure NewUserRepository with a database instead\n\tvar impl shop.UserRepository = mocks.NewMemoryUserRepository()\n",
+			"impl.GetByID(ctx, 0)",
+		}},
+		{"user_usecase_bench_test.go", []string{
+			"package shop_test\n",
+			"func BenchmarkUserUseCase_Remove(b *testing.B) {\n\trepo := mocks.NewMemoryUserRepository()\n\t// TODO: Store representative entities in repo\n\timpl := shop.NewUserUseCase(repo)\n",
+			// Variadic parameters are left out
+			`impl.Register(ctx, "")`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			content, exists := files[tt.file]
+			if !exists {
+				t.Fatalf("%s not generated", tt.file)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("%s does not contain\n%s\n\ngot:\n%s", tt.file, want, content)
+				}
+			}
+			if strings.Contains(content, "(nil)") {
+				t.Errorf("%s builds an implementation on nil dependencies:\n%s", tt.file, content)
+			}
+		})
+	}
+
+	if _, exists := files["user_handler_bench_test.go"]; exists {
+		t.Error("handlers get benchmarks")
+	}
+	if _, exists := files["mocks/user_repository_memory.gen.go"]; !exists {
+		t.Error("in-memory repository not generated")
+	}
+}
+
+func TestGenerateMemoryRepository(t *testing.T) {
+	files := generate(t, Options{Benchmarks: true}, analyze(t, map[string]string{"user.go": userRepositorySource}))
+	content := files["mocks/user_repository_memory.gen.go"]
+
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{"GetByID", `func (m *MemoryUserRepository) GetByID(ctx context.Context, id int64) (*shop.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, stored := range m.entities {
+		if stored.ID == id {
+			entity := stored
+			return &entity, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}`},
+		{"FindByAddress", `func (m *MemoryUserRepository) FindByAddress(ctx context.Context, address string) (*shop.User, error) {
+	// Not stored in memory
+	return nil, nil
+}`},
+		{"ListByName", `func (m *MemoryUserRepository) ListByName(ctx context.Context, name string) ([]shop.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var entities []shop.User
+	for _, stored := range m.entities {
+		if stored.Name == name {
+			entity := stored
+			entities = append(entities, entity)
+		}
+	}
+	return entities, nil
+}`},
+		{"Create", `func (m *MemoryUserRepository) Create(ctx context.Context, user *shop.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entities = append(m.entities, *user)
+	return nil
+}`},
+		{"Update", `func (m *MemoryUserRepository) Update(ctx context.Context, user shop.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, stored := range m.entities {
+		if stored.ID == user.ID {
+			m.entities[i] = user
+		}
+	}
+	return nil
+}`},
+		{"Delete", `func (m *MemoryUserRepository) Delete(ctx context.Context, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.entities[:0]
+	for _, stored := range m.entities {
+		if stored.ID != userID {
+			kept = append(kept, stored)
+		}
+	}
+	m.entities = kept
+	return nil
+}`},
+		{"Count", `func (m *MemoryUserRepository) Count(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := len(m.entities)
+	return count, nil
+}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := funcSource(t, content, tt.method); got != tt.want {
+				t.Errorf("got:\n%s\n\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateBenchmarkInPackageMain(t *testing.T) {
+	source := strings.Replace(shopSource, "package shop", "package main", 1) + "\nfunc main() {}\n"
+	files := generate(t, Options{Benchmarks: true}, analyze(t, map[string]string{"main.go": source}))
+
+	memory, exists := files["user_repository_memory_test.go"]
+	if !exists || !strings.Contains(memory, "package main\n") {
+		t.Fatalf("in-memory repository of package main not generated next to it:\n%s", memory)
+	}
+	bench := files["user_usecase_bench_test.go"]
+	if !strings.Contains(bench, "package main\n") || !strings.Contains(bench, "repo := NewMemoryUserRepository()\n") {
+		t.Errorf("benchmark of package main does not use the in-memory repository:\n%s", bench)
+	}
+}
@@ -0,0 +1,204 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// handwrittenConstructor returns the project's own constructor for the
+// interface with the given key: a function named New<Interface> in its
+// package, a New* function whose first result is the interface, or a
+// New<Interface> function of another package when no other interface has that
+// name. Interfaces with a handwritten constructor are already implemented, so
+// only the factory and wire providers are generated.
+func (g *Generator) handwrittenConstructor(key string, projectInfo *types.ProjectInfo) *types.FuncInfo {
+	dir, name := types.SplitKey(key)
+	if fn, exists := projectInfo.Functions[types.Key(dir, "New"+name)]; exists {
+		return fn
+	}
+
+	keys := make([]string, 0, len(projectInfo.Functions))
+	for fnKey := range projectInfo.Functions {
+		keys = append(keys, fnKey)
+	}
+	sort.Strings(keys)
+
+	for _, fnKey := range keys {
+		if fn := projectInfo.Functions[fnKey]; g.returnsInterface(fn, key, projectInfo) {
+			return fn
+		}
+	}
+
+	if len(named(projectInfo.Interfaces, name, "", projectInfo)) == 1 {
+		if fnKeys := named(projectInfo.Functions, "New"+name, "", projectInfo); len(fnKeys) == 1 {
+			return projectInfo.Functions[fnKeys[0]]
+		}
+	}
+
+	return nil
+}
+
+// returnsInterface reports whether the first result of fn is the interface
+// with the given key
+func (g *Generator) returnsInterface(fn *types.FuncInfo, key string, projectInfo *types.ProjectInfo) bool {
+	if len(fn.Returns) == 0 {
+		return false
+	}
+	returned, exists := g.analyzedInterface(fn.Returns[0].Type, funcScope(fn), projectInfo)
+	return exists && returned == key
+}
+
+// analyzedInterface returns the key of the analyzed interface typeName refers
+// to from scope, either unqualified or qualified with its package name
+func (g *Generator) analyzedInterface(typeName string, scope typeScope, projectInfo *types.ProjectInfo) (string, bool) {
+	return lookup(projectInfo.Interfaces, typeName, scope, projectInfo)
+}
+
+// interfaceDependency returns the analyzed interface with the given key as
+// referred to from the package the interface dependent on it is declared in
+func (g *Generator) interfaceDependency(key string, dependent *types.InterfaceInfo, projectInfo *types.ProjectInfo) string {
+	interfaceInfo := projectInfo.Interfaces[key]
+	return qualifier(g.sourcePackage(interfaceInfo, projectInfo), g.sourcePackage(dependent, projectInfo)) + interfaceInfo.Name
+}
+
+// factoryBody collects the statements of a factory method. Factory methods
+// that can fail are called before the value is built, returning their error.
+type factoryBody struct {
+	projectInfo *types.ProjectInfo
+	from        goPackage
+	fields      map[string]string // factory field type -> name
+	cache       map[string]bool   // interface key -> factory method returns an error
+	statements  strings.Builder
+	names       map[string]bool // variables declared so far
+	fails       bool            // the method returns an error
+}
+
+// newFactoryBody returns an empty factory method body. Its variables must not
+// shadow the receiver, the package names of the factory file or the names
+// the method declares itself.
+func newFactoryBody(projectInfo *types.ProjectInfo, from goPackage, fields map[string]string, cache map[string]bool) *factoryBody {
+	names := map[string]bool{"f": true, "err": true, "impl": true, "repo": true, "useCase": true}
+	for _, pkg := range generatedPackages {
+		names[pkg] = true
+	}
+	for _, pkg := range projectInfo.Packages {
+		names[pkg] = true
+	}
+	for alias := range projectInfo.Imports {
+		names[alias] = true
+	}
+	return &factoryBody{projectInfo: projectInfo, from: from, fields: fields, cache: cache, names: names}
+}
+
+// ret writes the return statement of the factory method
+func (body *factoryBody) ret(value string) {
+	if body.fails {
+		value += ", nil"
+	}
+	body.statements.WriteString(fmt.Sprintf("\treturn %s\n", value))
+}
+
+// variable declares a variable named after name, numbered when the name is
+// taken or not a valid variable name
+func (body *factoryBody) variable(name string) string {
+	variable := name
+	for i := 2; body.names[variable] || token.IsKeyword(variable) || predeclared[variable]; i++ {
+		variable = fmt.Sprintf("%s%d", name, i)
+	}
+	body.names[variable] = true
+	return variable
+}
+
+// call writes the call of the factory method of the interface with the
+// given key into variable, returning its error when it can fail
+func (g *Generator) call(body *factoryBody, variable, key string) {
+	name := g.factoryName(key, body.projectInfo)
+	if !g.factoryFails(body, key) {
+		body.statements.WriteString(fmt.Sprintf("\t%s := f.New%s()\n", variable, name))
+		return
+	}
+	body.fails = true
+	body.statements.WriteString(fmt.Sprintf("\t%s, err := f.New%s()\n", variable, name))
+	body.statements.WriteString("\tif err != nil {\n")
+	body.statements.WriteString("\t\treturn nil, err\n")
+	body.statements.WriteString("\t}\n")
+}
+
+// factoryFails reports whether the factory method of the interface with the
+// given key returns an error. Dependency cycles count as not failing.
+func (g *Generator) factoryFails(body *factoryBody, key string) bool {
+	if fails, known := body.cache[key]; known {
+		return fails
+	}
+	body.cache[key] = false
+	dependency := g.factoryMethodBody(key, body.projectInfo.Interfaces[key], body.projectInfo, body.from, body.fields, body.cache)
+	body.cache[key] = dependency.fails
+	return dependency.fails
+}
+
+// dependency returns the expression providing the interface with the given
+// key: a call of its factory method, or a variable holding its result when
+// the method can fail
+func (g *Generator) dependency(body *factoryBody, key string) string {
+	if !g.factoryFails(body, key) {
+		return fmt.Sprintf("f.New%s()", g.factoryName(key, body.projectInfo))
+	}
+	variable := body.variable(g.generateStructName(g.factoryName(key, body.projectInfo)))
+	g.call(body, variable, key)
+	return variable
+}
+
+// constructorArgs resolves the arguments of a handwritten constructor from the
+// factory: analyzed interfaces come from their factory methods, external
+// dependencies from factory fields, and parameter structs declared in the
+// project are built field by field.
+func (g *Generator) constructorArgs(fn *types.FuncInfo, body *factoryBody) []string {
+	prefix := qualifier(g.declPackage(fn.Package, fn.FilePath, body.projectInfo), body.from)
+
+	var args []string
+	for _, param := range fn.Params {
+		args = append(args, g.resolveDependency(param.Type, prefix, funcScope(fn), body, true))
+	}
+	return args
+}
+
+// resolveDependency returns the factory expression providing a value of
+// typeName, written in scope, adding the statements it needs to body.
+// expandStructs allows one level of parameter struct expansion.
+func (g *Generator) resolveDependency(typeName, prefix string, scope typeScope, body *factoryBody, expandStructs bool) string {
+	projectInfo := body.projectInfo
+	if key, exists := g.analyzedInterface(typeName, scope, projectInfo); exists {
+		return g.dependency(body, key)
+	}
+
+	structName := strings.TrimPrefix(typeName, "*")
+	structKey, isStruct := lookup(projectInfo.Structs, structName, scope, projectInfo)
+	if structInfo := projectInfo.Structs[structKey]; isStruct && expandStructs && structInfo.Name != configName {
+		var values []string
+		for _, field := range structInfo.Fields {
+			if field.Embedded || field.Name == "" {
+				continue
+			}
+			value := g.resolveDependency(field.Type, prefix, typeScope{dir: path.Dir(structInfo.FilePath)}, body, false)
+			values = append(values, fmt.Sprintf("%s: %s", field.Name, value))
+		}
+
+		literal := fmt.Sprintf("%s{%s}", qualifyType(structName, prefix), strings.Join(values, ", "))
+		if strings.HasPrefix(typeName, "*") {
+			return "&" + literal
+		}
+		return literal
+	}
+
+	qualified := g.qualifyDependency(typeName, prefix, projectInfo, g.layerPackage(diPackageKey, projectInfo))
+	if name, exists := body.fields[qualified]; exists {
+		return "f." + name
+	}
+
+	return fmt.Sprintf("%s /* TODO: provide %s */", g.generateZeroValue(qualified, projectInfo), qualified)
+}
@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// packageSelector matches package qualifiers such as "context." in type strings
+var packageSelector = regexp.MustCompile(`\b([a-zA-Z_][a-zA-Z0-9_]*)\.`)
+
+// ExtractInterface generates an interface matching the exported methods of
+// structName, plus a compile-time assertion that the struct implements it.
+// The file is written next to the struct and is meant to be owned and edited
+// by the user, so it does not carry the generated-code header.
+// structName may be qualified with the package name, or the package directory
+// (internal/user.userService), to pick one of same-named structs.
+func (g *Generator) ExtractInterface(structName, interfaceName string, projectInfo *types.ProjectInfo) (*GeneratedFile, error) {
+	key := structName
+	if !has(projectInfo.Structs, key) {
+		var exists bool
+		if key, exists = lookup(projectInfo.Structs, structName, typeScope{dir: "."}, projectInfo); !exists {
+			pkg, name, qualified := strings.Cut(structName, ".")
+			if !qualified {
+				pkg, name = "", structName
+			}
+			if candidates := named(projectInfo.Structs, name, pkg, projectInfo); len(candidates) > 1 {
+				return nil, fmt.Errorf("struct %s is declared in several packages, qualify it as one of %s", structName, strings.Join(candidates, ", "))
+			}
+			return nil, fmt.Errorf("struct %s not found", structName)
+		}
+	}
+	structInfo := projectInfo.Structs[key]
+	structName = structInfo.Name
+	if len(structInfo.Methods) == 0 {
+		return nil, fmt.Errorf("struct %s has no exported methods", structName)
+	}
+
+	dir := filepath.ToSlash(filepath.Dir(structInfo.FilePath))
+	if interfaceName == "" {
+		interfaceName = g.defaultInterfaceName(structName)
+	}
+	if _, exists := projectInfo.Interfaces[types.Key(dir, interfaceName)]; exists {
+		return nil, fmt.Errorf("interface %s already exists", interfaceName)
+	}
+	if _, exists := projectInfo.Structs[types.Key(dir, interfaceName)]; exists {
+		return nil, fmt.Errorf("type %s already exists", interfaceName)
+	}
+
+	// Imports referenced by method signatures
+	importPaths := make(map[string]bool)
+	for _, method := range structInfo.Methods {
+		g.addSignatureImports(method, projectInfo.Imports, importPaths)
+	}
+
+	var content strings.Builder
+
+	g.writeLicenseHeader(&content)
+	content.WriteString(fmt.Sprintf("// Code extracted by code-gen from %s.\n\n", structName))
+	content.WriteString(fmt.Sprintf("package %s\n\n", structInfo.Package))
+
+	if len(importPaths) > 0 {
+		content.WriteString("import (\n")
+		for _, imp := range importSpecs(importPaths) {
+			content.WriteString(fmt.Sprintf("\t%s\n", imp))
+		}
+		content.WriteString(")\n\n")
+	}
+
+	content.WriteString(fmt.Sprintf("// %s is the interface implemented by %s\n", interfaceName, structName))
+	content.WriteString(fmt.Sprintf("type %s interface {\n", interfaceName))
+
+	methods := append([]types.MethodInfo{}, structInfo.Methods...)
+	sort.SliceStable(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+
+	for i, method := range methods {
+		if i > 0 && len(method.Comments) > 0 {
+			content.WriteString("\n")
+		}
+		writeDocComment(&content, "\t", method.Comments)
+		content.WriteString(fmt.Sprintf("\t%s%s\n", method.Name, g.signature(method)))
+	}
+
+	content.WriteString("}\n\n")
+
+	content.WriteString(fmt.Sprintf("// Ensure %s implements %s\n", structName, interfaceName))
+	content.WriteString(fmt.Sprintf("var _ %s = (*%s)(nil)\n", interfaceName, structName))
+
+	return &GeneratedFile{
+		Filename:  path.Join(dir, strings.ToLower(structName)+"_interface.go"),
+		Content:   content.String(),
+		LineCount: strings.Count(content.String(), "\n"),
+	}, nil
+}
+
+// defaultInterfaceName derives an interface name for structName: unexported
+// structs are capitalized, exported ones get an "Interface" suffix
+func (g *Generator) defaultInterfaceName(structName string) string {
+	runes := []rune(structName)
+	if unicode.IsLower(runes[0]) {
+		runes[0] = unicode.ToUpper(runes[0])
+		return string(runes)
+	}
+	return structName + "Interface"
+}
+
+// signature renders the parameter and result lists of a method
+func (g *Generator) signature(method types.MethodInfo) string {
+	var params []string
+	for _, param := range method.Params {
+		params = append(params, strings.TrimSpace(param.Name+" "+param.Type))
+	}
+
+	result := "(" + strings.Join(params, ", ") + ")"
+
+	switch {
+	case len(method.Returns) == 1 && method.Returns[0].Name == "":
+		result += " " + method.Returns[0].Type
+	case len(method.Returns) > 0:
+		var returns []string
+		for _, ret := range method.Returns {
+			returns = append(returns, strings.TrimSpace(ret.Name+" "+ret.Type))
+		}
+		result += " (" + strings.Join(returns, ", ") + ")"
+	}
+
+	return result
+}
@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/navyarakshakarya/code-gen/logger"
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+func TestFactoryReturnsConstructionErrors(t *testing.T) {
+	g := New(logger.New(false, true), Options{})
+	projectInfo := &types.ProjectInfo{
+		ModuleName: "example.com/shop",
+		Packages:   map[string]string{".": "shop"},
+		Interfaces: map[string]*types.InterfaceInfo{
+			"ProductRepository": {Name: "ProductRepository", Package: "shop", FilePath: "shop.go", Layer: types.RepositoryLayer},
+			"ProductUseCase":    {Name: "ProductUseCase", Package: "shop", FilePath: "shop.go", Layer: types.UseCaseLayer},
+			"ProductHandler":    {Name: "ProductHandler", Package: "shop", FilePath: "shop.go", Layer: types.HandlerLayer},
+			"ReviewRepository":  {Name: "ReviewRepository", Package: "shop", FilePath: "shop.go", Layer: types.RepositoryLayer},
+		},
+		Structs: map[string]*types.StructInfo{},
+		Functions: map[string]*types.FuncInfo{
+			"NewProductRepository": {
+				Name:     "NewProductRepository",
+				Package:  "shop",
+				FilePath: "shop.go",
+				Params:   []types.ParamInfo{{Name: "db", Type: "*sql.DB"}},
+				Returns:  []types.ParamInfo{{Type: "ProductRepository"}, {Type: "error"}},
+			},
+		},
+	}
+
+	file, err := g.generateFactory(projectInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"func (f *Factory) NewProductRepository() (ProductRepository, error) {\n\timpl, err := NewProductRepository(f.db)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\treturn impl, nil\n}",
+		"func (f *Factory) NewProductUseCase() (ProductUseCase, error) {\n\trepo, err := f.NewProductRepository()\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\treturn NewProductUseCase(repo), nil\n}",
+		"func (f *Factory) NewProductHandler() (ProductHandler, error) {\n\tuseCase, err := f.NewProductUseCase()\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\treturn NewProductHandler(useCase), nil\n}",
+		"func (f *Factory) NewReviewRepository() ReviewRepository {\n",
+	} {
+		if !strings.Contains(file.Content, want) {
+			t.Errorf("factory does not contain\n%s\n\ngot:\n%s", want, file.Content)
+		}
+	}
+	if strings.Contains(file.Content, "panic(") {
+		t.Errorf("factory panics on construction errors:\n%s", file.Content)
+	}
+}
@@ -2,6 +2,10 @@ package generator
 
 import (
 	"fmt"
+	"go/format"
+	"path"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,7 +15,20 @@ import (
 
 // Generator generates clean architecture code
 type Generator struct {
-	logger *logger.Logger
+	logger  *logger.Logger
+	options Options
+}
+
+// Options controls optional generator output
+type Options struct {
+	Mode           string            // ModeImplementations (default) or ModeMocks
+	Benchmarks     bool              // generate benchmark stubs for repositories and use cases
+	Tests          bool              // generate table-driven tests with mocked dependencies
+	Layout         string            // LayoutFlat (default) or LayoutLayered
+	LayerDirs      map[string]string // overrides DefaultLayerDirs in the layered layout
+	BaseImportPath string            // import path of the output directory (default: module path)
+	Header         string            // comment block prepended to every generated file, e.g. a license
+	Style          Style             // naming and formatting of generated implementations
 }
 
 // GeneratedFile represents a generated file
@@ -19,28 +36,85 @@ type GeneratedFile struct {
 	Filename  string
 	Content   string
 	LineCount int
+	Scaffold  bool // written once as a starting point, then owned by the project
 }
 
 // New creates a new generator instance
-func New(logger *logger.Logger) *Generator {
+func New(logger *logger.Logger, options Options) *Generator {
 	return &Generator{
-		logger: logger,
+		logger:  logger,
+		options: options,
 	}
 }
 
-// Generate generates all code files
+// Generate generates all code files, formatted like gofmt
 func (g *Generator) Generate(projectInfo *types.ProjectInfo) ([]*GeneratedFile, error) {
+	results, err := g.generateFiles(projectInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range results {
+		content := g.applyCommentStyle(file.Content)
+		if strings.HasSuffix(file.Filename, ".go") {
+			formatted, err := format.Source([]byte(content))
+			if err != nil {
+				return nil, fmt.Errorf("generated %s is not valid Go: %w", file.Filename, err)
+			}
+			content = string(formatted)
+		}
+		file.Content = content
+		file.LineCount = strings.Count(file.Content, "\n")
+	}
+	return results, nil
+}
+
+// generateFiles generates the files of the configured mode
+func (g *Generator) generateFiles(projectInfo *types.ProjectInfo) ([]*GeneratedFile, error) {
+	if g.options.Mode == ModeMocks {
+		return g.generateMocks(projectInfo)
+	}
+
 	var results []*GeneratedFile
+	mocked := make(map[string]bool)
+	memory := make(map[string]bool)
 
 	// Generate implementations for each interface
-	for interfaceName, interfaceInfo := range projectInfo.Interfaces {
-		file, err := g.generateImplementation(interfaceName, interfaceInfo, projectInfo)
+	for _, key := range g.sortedInterfaces(projectInfo) {
+		interfaceInfo := projectInfo.Interfaces[key]
+		if fn := g.handwrittenConstructor(key, projectInfo); fn != nil {
+			g.logger.Info("Using %s from %s for %s", fn.Name, fn.FilePath, key)
+			continue
+		}
+
+		file, err := g.generateImplementation(key, interfaceInfo, projectInfo)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate implementation for %s: %w", interfaceName, err)
+			return nil, fmt.Errorf("failed to generate implementation for %s: %w", key, err)
 		}
 		results = append(results, file)
+
+		if g.options.Benchmarks && (interfaceInfo.Layer == types.RepositoryLayer || interfaceInfo.Layer == types.UseCaseLayer) {
+			benchFile, repositories := g.generateBenchmark(key, interfaceInfo, projectInfo)
+			results = append(results, benchFile)
+			for _, repository := range repositories {
+				memory[repository] = true
+			}
+		}
+
+		if g.options.Tests {
+			testFile, dependencies := g.generateTest(key, interfaceInfo, projectInfo)
+			results = append(results, testFile)
+			for _, dependency := range dependencies {
+				mocked[dependency] = true
+			}
+		}
 	}
 
+	// Generate the mocks used by the tests and the in-memory repositories
+	// used by the benchmarks
+	results = append(results, g.generateTestMocks(mocked, projectInfo)...)
+	results = append(results, g.generateMemoryRepositories(memory, projectInfo)...)
+
 	// Generate factory
 	factoryFile, err := g.generateFactory(projectInfo)
 	if err != nil {
@@ -55,21 +129,87 @@ func (g *Generator) Generate(projectInfo *types.ProjectInfo) ([]*GeneratedFile,
 	}
 	results = append(results, wireFile)
 
+	// Generate a placeholder Config when a constructor needs one the project
+	// does not declare
+	if _, declared := g.configPackage(projectInfo); !declared && g.needsConfig(projectInfo) {
+		results = append(results, g.generateConfig(projectInfo))
+	}
+
 	return results, nil
 }
 
-// generateImplementation generates implementation for an interface
-func (g *Generator) generateImplementation(interfaceName string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) (*GeneratedFile, error) {
+// generateImplementation generates implementation for the interface with
+// the given key
+func (g *Generator) generateImplementation(key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) (*GeneratedFile, error) {
+	target := g.implPackage(interfaceInfo, projectInfo)
+	source := g.sourcePackage(interfaceInfo, projectInfo)
+	prefix := qualifier(source, target)
+
+	interfaceName := interfaceInfo.Name
 	structName := g.generateStructName(interfaceName)
-	fileName := g.generateFileName(interfaceName, interfaceInfo.Layer)
+	fileName := path.Join(target.dir, g.generateFileName(interfaceName, interfaceInfo.Layer))
+	entity := g.repositoryEntity(key, interfaceInfo, projectInfo)
+
+	var body strings.Builder
+
+	// Struct definition
+	g.writeStructDefinition(&body, structName, key, interfaceInfo, projectInfo, prefix)
+
+	// Constructor
+	g.writeConstructor(&body, structName, key, interfaceInfo, projectInfo, prefix)
+
+	// Method implementations
+	for _, method := range interfaceInfo.Methods {
+		if method.Skip {
+			body.WriteString(fmt.Sprintf("// %s is marked //codegen:skip and must be implemented by hand\n\n", method.Name))
+			continue
+		}
+		g.writeMethodImplementation(&body, structName, method, interfaceInfo.Layer, entity, prefix, projectInfo)
+	}
+
+	// Interface compliance check
+	body.WriteString(fmt.Sprintf("// Ensure %s implements %s\n", structName, interfaceName))
+	if g.options.Style.ValueReceivers {
+		body.WriteString(fmt.Sprintf("var _ %s = %s{}\n", qualifyType(interfaceName, prefix), structName))
+	} else {
+		body.WriteString(fmt.Sprintf("var _ %s = (*%s)(nil)\n", qualifyType(interfaceName, prefix), structName))
+	}
 
 	var content strings.Builder
 
 	// File header
-	g.writeFileHeader(&content, projectInfo.PackageName)
+	g.writeFileHeader(&content, target.name)
 
-	// Imports
-	imports := g.generateImports(interfaceInfo, projectInfo)
+	// Imports, dropping those the method bodies ended up not using, plus the
+	// project packages the implementation refers to
+	paths := make(map[string]bool)
+	if prefix != "" {
+		paths[source.importPath] = true
+	}
+	for _, method := range interfaceInfo.Methods {
+		g.addSignatureImports(method, g.fileImports(interfaceInfo, projectInfo), paths)
+	}
+	for _, dep := range g.generateDependencies(key, interfaceInfo, projectInfo) {
+		parts := strings.Fields(dep)
+		if len(parts) < 2 {
+			continue
+		}
+		if depKey, exists := g.analyzedInterface(parts[1], typeScope{dir: source.dir}, projectInfo); exists {
+			paths[g.sourcePackage(projectInfo.Interfaces[depKey], projectInfo).importPath] = true
+		}
+	}
+	if entity != nil && entity.importPath != "" {
+		paths[entity.importPath] = true
+	}
+	delete(paths, target.importPath)
+
+	imports := usedImports(g.generateImports(interfaceInfo, entity, projectInfo), body.String())
+	for _, spec := range importSpecs(paths) {
+		if !slices.Contains(imports, spec) {
+			imports = append(imports, spec)
+		}
+	}
+	sort.Strings(imports)
 	if len(imports) > 0 {
 		content.WriteString("import (\n")
 		for _, imp := range imports {
@@ -78,20 +218,7 @@ func (g *Generator) generateImplementation(interfaceName string, interfaceInfo *
 		content.WriteString(")\n\n")
 	}
 
-	// Struct definition
-	g.writeStructDefinition(&content, structName, interfaceName, interfaceInfo, projectInfo)
-
-	// Constructor
-	g.writeConstructor(&content, structName, interfaceName, interfaceInfo, projectInfo)
-
-	// Method implementations
-	for _, method := range interfaceInfo.Methods {
-		g.writeMethodImplementation(&content, structName, method, interfaceInfo.Layer)
-	}
-
-	// Interface compliance check
-	content.WriteString(fmt.Sprintf("// Ensure %s implements %s\n", structName, interfaceName))
-	content.WriteString(fmt.Sprintf("var _ %s = (*%s)(nil)\n", interfaceName, structName))
+	content.WriteString(body.String())
 
 	return &GeneratedFile{
 		Filename:  fileName,
@@ -100,87 +227,70 @@ func (g *Generator) generateImplementation(interfaceName string, interfaceInfo *
 	}, nil
 }
 
-// generateFactory generates the dependency injection factory
+// generateFactory generates the dependency injection factory. Its fields are
+// the external dependencies of the analyzed constructors.
 func (g *Generator) generateFactory(projectInfo *types.ProjectInfo) (*GeneratedFile, error) {
-	var content strings.Builder
-
-	g.writeFileHeader(&content, projectInfo.PackageName)
+	diPkg := g.layerPackage(diPackageKey, projectInfo)
+	deps, imports := g.factoryDependencies(projectInfo)
+
+	var names, typeNames []string
+	width := 0
+	for _, param := range deps.params {
+		name, typeName, _ := strings.Cut(param, " ")
+		names = append(names, name)
+		typeNames = append(typeNames, typeName)
+		width = max(width, len(name))
+	}
 
-	// Imports
-	content.WriteString("import (\n")
-	content.WriteString("\t\"database/sql\"\n")
-	content.WriteString("\t\"context\"\n")
-	content.WriteString(")\n\n")
+	var body strings.Builder
 
 	// Factory struct
-	content.WriteString("// Factory provides centralized dependency injection\n")
-	content.WriteString("// This follows the factory pattern for clean architecture\n")
-	content.WriteString("type Factory struct {\n")
-	content.WriteString("\tdb     *sql.DB\n")
-	content.WriteString("\tctx    context.Context\n")
-	content.WriteString("\tconfig *Config // Add your config struct\n")
-	content.WriteString("}\n\n")
+	body.WriteString("// Factory provides centralized dependency injection\n")
+	body.WriteString("// This follows the factory pattern for clean architecture\n")
+	body.WriteString("type Factory struct {\n")
+	for i, name := range names {
+		body.WriteString(fmt.Sprintf("\t%-*s %s\n", width, name, typeNames[i]))
+	}
+	body.WriteString("}\n\n")
 
 	// Factory constructor
-	content.WriteString("// NewFactory creates a new factory instance\n")
-	content.WriteString("func NewFactory(db *sql.DB, ctx context.Context, config *Config) *Factory {\n")
-	content.WriteString("\treturn &Factory{\n")
-	content.WriteString("\t\tdb:     db,\n")
-	content.WriteString("\t\tctx:    ctx,\n")
-	content.WriteString("\t\tconfig: config,\n")
-	content.WriteString("\t}\n")
-	content.WriteString("}\n\n")
+	body.WriteString("// NewFactory creates a new factory instance\n")
+	body.WriteString(fmt.Sprintf("func NewFactory(%s) *Factory {\n", strings.Join(deps.params, ", ")))
+	body.WriteString("\treturn &Factory{\n")
+	for _, name := range names {
+		body.WriteString(fmt.Sprintf("\t\t%-*s %s,\n", width+1, name+":", name))
+	}
+	body.WriteString("\t}\n")
+	body.WriteString("}\n\n")
 
 	// Generate factory methods for each interface
-	for interfaceName, interfaceInfo := range projectInfo.Interfaces {
-		g.writeFactoryMethod(&content, interfaceName, interfaceInfo, projectInfo)
+	fails := make(map[string]bool)
+	for _, key := range g.sortedInterfaces(projectInfo) {
+		g.writeFactoryMethod(&body, key, projectInfo.Interfaces[key], projectInfo, diPkg, deps.types, fails)
 	}
 
-	return &GeneratedFile{
-		Filename:  "factory.gen.go",
-		Content:   content.String(),
-		LineCount: strings.Count(content.String(), "\n"),
-	}, nil
-}
-
-// generateWireIntegration generates Wire-compatible provider functions
-func (g *Generator) generateWireIntegration(projectInfo *types.ProjectInfo) (*GeneratedFile, error) {
 	var content strings.Builder
 
-	g.writeFileHeader(&content, projectInfo.PackageName)
-
-	// Wire build constraint
-	content.WriteString("//go:build wireinject\n")
-	content.WriteString("// +build wireinject\n\n")
+	g.writeFileHeader(&content, diPkg.name)
 
-	// Imports
-	content.WriteString("import (\n")
-	content.WriteString("\t\"database/sql\"\n")
-	content.WriteString("\t\"context\"\n")
-	content.WriteString("\t\"github.com/google/wire\"\n")
-	content.WriteString(")\n\n")
+	// Let `go generate` run Wire on the injectors in wire.go
+	content.WriteString("//go:generate go run github.com/google/wire/cmd/wire\n\n")
 
-	// Provider set
-	content.WriteString("// ProviderSet is the Wire provider set for dependency injection\n")
-	content.WriteString("var ProviderSet = wire.NewSet(\n")
-
-	for interfaceName := range projectInfo.Interfaces {
-		constructorName := "New" + interfaceName
-		content.WriteString(fmt.Sprintf("\t%s,\n", constructorName))
+	for _, spec := range usedImports(g.diImports(diPkg, projectInfo), body.String()) {
+		imports[strings.Trim(spec, "\"")] = true
 	}
-
-	content.WriteString("\tNewFactory,\n")
-	content.WriteString(")\n\n")
-
-	// Wire injector functions
-	for interfaceName, interfaceInfo := range projectInfo.Interfaces {
-		if interfaceInfo.Layer == types.HandlerLayer {
-			g.writeWireInjector(&content, interfaceName, projectInfo)
+	if len(imports) > 0 {
+		content.WriteString("import (\n")
+		for _, spec := range importSpecs(imports) {
+			content.WriteString(fmt.Sprintf("\t%s\n", spec))
 		}
+		content.WriteString(")\n\n")
 	}
 
+	content.WriteString(body.String())
+
 	return &GeneratedFile{
-		Filename:  "wire.gen.go",
+		Filename:  path.Join(diPkg.dir, "factory.gen.go"),
 		Content:   content.String(),
 		LineCount: strings.Count(content.String(), "\n"),
 	}, nil
@@ -188,7 +298,34 @@ func (g *Generator) generateWireIntegration(projectInfo *types.ProjectInfo) (*Ge
 
 // Helper methods for code generation
 
+// writeLicenseHeader writes Options.Header followed by a blank line, so it is
+// not mistaken for a package comment
+func (g *Generator) writeLicenseHeader(content *strings.Builder) {
+	if g.options.Header == "" {
+		return
+	}
+	content.WriteString(strings.TrimRight(g.options.Header, "\n") + "\n\n")
+}
+
 func (g *Generator) writeFileHeader(content *strings.Builder, packageName string) {
+	g.writeConstrainedFileHeader(content, packageName, "")
+}
+
+// writeScaffoldHeader writes the header of a scaffold file, which is not
+// marked as generated since it is meant to be edited
+func (g *Generator) writeScaffoldHeader(content *strings.Builder, packageName string) {
+	g.writeLicenseHeader(content)
+	content.WriteString("// Scaffolded by code-gen. Edit freely: code-gen does not overwrite this file.\n\n")
+	content.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+}
+
+// writeConstrainedFileHeader writes the file header with a //go:build
+// constraint, which must precede the package clause
+func (g *Generator) writeConstrainedFileHeader(content *strings.Builder, packageName, constraint string) {
+	g.writeLicenseHeader(content)
+	if constraint != "" {
+		content.WriteString(fmt.Sprintf("//go:build %s\n\n", constraint))
+	}
 	content.WriteString("// Code generated by code-gen. DO NOT EDIT.\n")
 	content.WriteString(fmt.Sprintf("// Generated at: %s\n\n", time.Now().Format(time.RFC3339)))
 	content.WriteString(fmt.Sprintf("package %s\n\n", packageName))
@@ -198,12 +335,19 @@ func (g *Generator) generateStructName(interfaceName string) string {
 	return strings.ToLower(string(interfaceName[0])) + interfaceName[1:]
 }
 
+// generateFileName names the file implementing an interface after its base
+// name and layer, or after the layer alone for interfaces named only by a
+// layer suffix (such as Repository), since the go tool ignores files whose
+// names start with "_"
 func (g *Generator) generateFileName(interfaceName string, layer types.LayerType) string {
 	baseName := g.extractBaseName(interfaceName)
+	if baseName == "" {
+		return fmt.Sprintf("%s.gen.go", layer)
+	}
 	return fmt.Sprintf("%s_%s.gen.go", strings.ToLower(baseName), layer)
 }
 
-func (g *Generator) generateImports(interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) []string {
+func (g *Generator) generateImports(interfaceInfo *types.InterfaceInfo, entity *entityInfo, projectInfo *types.ProjectInfo) []string {
 	imports := make(map[string]bool)
 
 	// Standard library imports
@@ -227,7 +371,13 @@ func (g *Generator) generateImports(interfaceInfo *types.InterfaceInfo, projectI
 	// Layer-specific imports
 	switch interfaceInfo.Layer {
 	case types.RepositoryLayer:
-		imports["\"database/sql\""] = true
+		if entity != nil && entity.mongo {
+			imports["\"go.mongodb.org/mongo-driver/bson\""] = true
+			imports["\"go.mongodb.org/mongo-driver/mongo\""] = true
+		} else {
+			imports["\"database/sql\""] = true
+		}
+		imports["\"errors\""] = true
 		imports["\"fmt\""] = true
 	case types.UseCaseLayer:
 		imports["\"fmt\""] = true
@@ -236,11 +386,12 @@ func (g *Generator) generateImports(interfaceInfo *types.InterfaceInfo, projectI
 		imports["\"net/http\""] = true
 	}
 
-	// Convert to slice
+	// Convert to slice, sorted so regenerating unchanged input yields the same file
 	var result []string
 	for imp := range imports {
 		result = append(result, imp)
 	}
+	sort.Strings(result)
 
 	return result
 }
@@ -260,31 +411,28 @@ func (g *Generator) addFrameworkImports(typeName string, imports map[string]bool
 	}
 }
 
-func (g *Generator) writeStructDefinition(content *strings.Builder, structName, interfaceName string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) {
+func (g *Generator) writeStructDefinition(content *strings.Builder, structName, key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo, prefix string) {
 	// Comments
-	if len(interfaceInfo.Comments) > 0 {
-		for _, comment := range interfaceInfo.Comments {
-			content.WriteString(fmt.Sprintf("// %s\n", strings.TrimSpace(comment)))
-		}
-	}
+	writeDocComment(content, "", interfaceInfo.Comments)
 
-	content.WriteString(fmt.Sprintf("// %s implements %s interface\n", structName, interfaceName))
+	content.WriteString(fmt.Sprintf("// %s implements %s interface\n", structName, interfaceInfo.Name))
 	content.WriteString(fmt.Sprintf("type %s struct {\n", structName))
 
 	// Dependencies
-	dependencies := g.generateDependencies(interfaceName, interfaceInfo, projectInfo)
+	dependencies := g.generateDependencies(key, interfaceInfo, projectInfo)
 	for _, dep := range dependencies {
-		content.WriteString(fmt.Sprintf("\t%s\n", dep))
+		content.WriteString(fmt.Sprintf("\t%s\n", qualifyType(dep, prefix)))
 	}
 
 	content.WriteString("}\n\n")
 }
 
-func (g *Generator) writeConstructor(content *strings.Builder, structName, interfaceName string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) {
-	dependencies := g.generateDependencies(interfaceName, interfaceInfo, projectInfo)
+func (g *Generator) writeConstructor(content *strings.Builder, structName, key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo, prefix string) {
+	dependencies := g.generateDependencies(key, interfaceInfo, projectInfo)
 
-	content.WriteString(fmt.Sprintf("// New%s creates a new instance of %s\n", interfaceName, structName))
-	content.WriteString(fmt.Sprintf("func New%s(", interfaceName))
+	constructor := g.constructorName(interfaceInfo.Name)
+	content.WriteString(fmt.Sprintf("// %s creates a new instance of %s\n", constructor, structName))
+	content.WriteString(fmt.Sprintf("func %s(", constructor))
 
 	// Parameters
 	var params []string
@@ -294,15 +442,19 @@ func (g *Generator) writeConstructor(content *strings.Builder, structName, inter
 		parts := strings.Fields(dep)
 		if len(parts) >= 2 {
 			fieldName := parts[0]
-			fieldType := strings.Join(parts[1:], " ")
+			fieldType := qualifyType(strings.Join(parts[1:], " "), prefix)
 			params = append(params, fmt.Sprintf("%s %s", fieldName, fieldType))
 			assignments = append(assignments, fmt.Sprintf("\t\t%s: %s,", fieldName, fieldName))
 		}
 	}
 
 	content.WriteString(strings.Join(params, ", "))
-	content.WriteString(fmt.Sprintf(") %s {\n", interfaceName))
-	content.WriteString(fmt.Sprintf("\treturn &%s{\n", structName))
+	content.WriteString(fmt.Sprintf(") %s {\n", qualifyType(interfaceInfo.Name, prefix)))
+	if g.options.Style.ValueReceivers {
+		content.WriteString(fmt.Sprintf("\treturn %s{\n", structName))
+	} else {
+		content.WriteString(fmt.Sprintf("\treturn &%s{\n", structName))
+	}
 
 	for _, assignment := range assignments {
 		content.WriteString(assignment + "\n")
@@ -312,18 +464,26 @@ func (g *Generator) writeConstructor(content *strings.Builder, structName, inter
 	content.WriteString("}\n\n")
 }
 
-func (g *Generator) writeMethodImplementation(content *strings.Builder, structName string, method types.MethodInfo, layer types.LayerType) {
+func (g *Generator) writeMethodImplementation(content *strings.Builder, structName string, method types.MethodInfo, layer types.LayerType, entity *entityInfo, prefix string, projectInfo *types.ProjectInfo) {
+	// Doc comment of the interface method, if any
+	if len(method.Comments) > 0 {
+		writeDocComment(content, "", method.Comments)
+	} else {
+		content.WriteString(fmt.Sprintf("// %s implements the %s method\n", method.Name, method.Name))
+	}
+
 	// Method signature
-	content.WriteString(fmt.Sprintf("// %s implements the %s method\n", method.Name, method.Name))
-	content.WriteString(fmt.Sprintf("func (impl *%s) %s(", structName, method.Name))
+	recv := g.receiver(structName)
+	content.WriteString(fmt.Sprintf("func (%s %s) %s(", recv, g.receiverType(structName), method.Name))
 
 	// Parameters
 	var params []string
 	for _, param := range method.Params {
+		paramType := qualifyType(param.Type, prefix)
 		if param.Name != "" {
-			params = append(params, fmt.Sprintf("%s %s", param.Name, param.Type))
+			params = append(params, fmt.Sprintf("%s %s", param.Name, paramType))
 		} else {
-			params = append(params, param.Type)
+			params = append(params, paramType)
 		}
 	}
 	content.WriteString(strings.Join(params, ", "))
@@ -334,10 +494,11 @@ func (g *Generator) writeMethodImplementation(content *strings.Builder, structNa
 		content.WriteString(" (")
 		var returns []string
 		for _, ret := range method.Returns {
+			retType := qualifyType(ret.Type, prefix)
 			if ret.Name != "" {
-				returns = append(returns, fmt.Sprintf("%s %s", ret.Name, ret.Type))
+				returns = append(returns, fmt.Sprintf("%s %s", ret.Name, retType))
 			} else {
-				returns = append(returns, ret.Type)
+				returns = append(returns, retType)
 			}
 		}
 		content.WriteString(strings.Join(returns, ", "))
@@ -347,19 +508,27 @@ func (g *Generator) writeMethodImplementation(content *strings.Builder, structNa
 	content.WriteString(" {\n")
 
 	// Method body with layer-specific templates
-	g.writeMethodBody(content, method, layer)
+	g.writeMethodBody(content, recv, method, layer, entity, prefix, projectInfo)
 
 	content.WriteString("}\n\n")
 }
 
-func (g *Generator) writeMethodBody(content *strings.Builder, method types.MethodInfo, layer types.LayerType) {
+func (g *Generator) writeMethodBody(content *strings.Builder, recv string, method types.MethodInfo, layer types.LayerType, entity *entityInfo, prefix string, projectInfo *types.ProjectInfo) {
+	// Repository methods operating on a known entity get a runnable body
+	if entity != nil {
+		if body, ok := g.repositoryMethodBody(recv, method, entity, prefix, projectInfo); ok {
+			content.WriteString(body)
+			return
+		}
+	}
+
 	content.WriteString(fmt.Sprintf("\t// TODO: Implement %s\n", method.Name))
 
 	switch layer {
 	case types.RepositoryLayer:
 		content.WriteString("\t// Example database operation:\n")
 		content.WriteString("\t// query := \"SELECT * FROM table WHERE condition = ?\"\n")
-		content.WriteString("\t// rows, err := impl.db.QueryContext(ctx, query, param)\n")
+		content.WriteString(fmt.Sprintf("\t// rows, err := %s.db.QueryContext(ctx, query, param)\n", recv))
 		content.WriteString("\t// if err != nil {\n")
 		content.WriteString("\t//     return result, fmt.Errorf(\"database query failed: %w\", err)\n")
 		content.WriteString("\t// }\n")
@@ -378,83 +547,214 @@ func (g *Generator) writeMethodBody(content *strings.Builder, method types.Metho
 		content.WriteString("\t// 4. Return HTTP response\n")
 	}
 
-	// Generate return statement
-	if len(method.Returns) > 0 {
+	// Generate return statement; named results are already zero
+	if len(method.Returns) > 0 && method.Returns[0].Name != "" {
+		content.WriteString("\treturn\n")
+	} else if len(method.Returns) > 0 {
 		var returnValues []string
 		for _, ret := range method.Returns {
-			returnValues = append(returnValues, g.generateZeroValue(ret.Type))
+			returnValues = append(returnValues, g.generateZeroValue(qualifyType(ret.Type, prefix), projectInfo))
 		}
 		content.WriteString(fmt.Sprintf("\treturn %s\n", strings.Join(returnValues, ", ")))
 	}
 }
 
-func (g *Generator) writeFactoryMethod(content *strings.Builder, interfaceName string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) {
-	baseName := g.extractBaseName(interfaceName)
+// writeFactoryMethod writes the factory method building the interface with
+// the given key. It returns the interface, or the interface and an error when
+// constructing it or one of its dependencies can fail; fails caches which
+// factory methods return an error.
+func (g *Generator) writeFactoryMethod(content *strings.Builder, key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo, from goPackage, fields map[string]string, fails map[string]bool) {
+	body := g.factoryMethodBody(key, interfaceInfo, projectInfo, from, fields, fails)
+	fails[key] = body.fails
+
+	interfaceType := g.interfaceRef(interfaceInfo, projectInfo, from)
+	if body.fails {
+		interfaceType = fmt.Sprintf("(%s, error)", interfaceType)
+	}
+	name := g.factoryName(key, projectInfo)
 
-	content.WriteString(fmt.Sprintf("// New%s creates a new %s instance with dependencies\n", interfaceName, interfaceName))
-	content.WriteString(fmt.Sprintf("func (f *Factory) New%s() %s {\n", interfaceName, interfaceName))
+	content.WriteString(fmt.Sprintf("// New%s creates a new %s instance with dependencies\n", name, interfaceInfo.Name))
+	content.WriteString(fmt.Sprintf("func (f *Factory) New%s() %s {\n", name, interfaceType))
+	content.WriteString(body.statements.String())
+	content.WriteString("}\n\n")
+}
+
+// factoryMethodBody renders the statements of the factory method building the
+// interface with the given key
+func (g *Generator) factoryMethodBody(key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo, from goPackage, fields map[string]string, fails map[string]bool) *factoryBody {
+	baseName := g.extractBaseName(interfaceInfo.Name)
+	constructor := g.constructorRef(key, interfaceInfo, projectInfo, from)
+	source := g.sourcePackage(interfaceInfo, projectInfo)
+	body := newFactoryBody(projectInfo, from, fields, fails)
+
+	// Dependencies of handwritten constructors are inferred from their parameters
+	if fn := g.handwrittenConstructor(key, projectInfo); fn != nil {
+		args := strings.Join(g.constructorArgs(fn, body), ", ")
+		if len(fn.Returns) > 1 {
+			results := "impl, err"
+			if len(fn.Returns) > 2 {
+				// The factory has no lifecycle to run cleanup functions in
+				results = "impl, _, err"
+			}
+			body.fails = true
+			body.statements.WriteString(fmt.Sprintf("\t%s := %s(%s)\n", results, constructor, args))
+			body.statements.WriteString("\tif err != nil {\n")
+			body.statements.WriteString("\t\treturn nil, err\n")
+			body.statements.WriteString("\t}\n")
+			body.ret("impl")
+		} else {
+			body.ret(fmt.Sprintf("%s(%s)", constructor, args))
+		}
+		return body
+	}
 
 	switch interfaceInfo.Layer {
 	case types.RepositoryLayer:
-		content.WriteString(fmt.Sprintf("\treturn New%s(f.db)\n", interfaceName))
+		var args []string
+		for _, dep := range g.generateDependencies(key, interfaceInfo, projectInfo) {
+			if parts := strings.Fields(dep); len(parts) >= 2 {
+				args = append(args, g.resolveDependency(strings.Join(parts[1:], " "), qualifier(source, from), typeScope{dir: source.dir}, body, false))
+			}
+		}
+		body.ret(fmt.Sprintf("%s(%s)", constructor, strings.Join(args, ", ")))
 	case types.UseCaseLayer:
-		repoInterface := g.findRelatedInterface(baseName, types.RepositoryLayer, projectInfo)
+		repoInterface := g.findRelatedInterface(baseName, types.RepositoryLayer, source.dir, projectInfo)
 		if repoInterface != "" {
-			content.WriteString(fmt.Sprintf("\trepo := f.New%s()\n", repoInterface))
-			content.WriteString(fmt.Sprintf("\treturn New%s(repo)\n", interfaceName))
+			g.call(body, "repo", repoInterface)
+			body.ret(fmt.Sprintf("%s(repo)", constructor))
 		} else {
-			content.WriteString("\t// TODO: Add repository dependency\n")
-			content.WriteString(fmt.Sprintf("\treturn New%s(/* dependencies */)\n", interfaceName))
+			body.statements.WriteString("\t// TODO: Add repository dependency\n")
+			body.ret(fmt.Sprintf("%s(/* dependencies */)", constructor))
 		}
 	case types.HandlerLayer:
-		useCaseInterface := g.findRelatedInterface(baseName, types.UseCaseLayer, projectInfo)
+		useCaseInterface := g.findRelatedInterface(baseName, types.UseCaseLayer, source.dir, projectInfo)
 		if useCaseInterface != "" {
-			content.WriteString(fmt.Sprintf("\tuseCase := f.New%s()\n", useCaseInterface))
-			content.WriteString(fmt.Sprintf("\treturn New%s(useCase)\n", interfaceName))
+			g.call(body, "useCase", useCaseInterface)
+			body.ret(fmt.Sprintf("%s(useCase)", constructor))
 		} else {
-			content.WriteString("\t// TODO: Add use case dependency\n")
-			content.WriteString(fmt.Sprintf("\treturn New%s(/* dependencies */)\n", interfaceName))
+			body.statements.WriteString("\t// TODO: Add use case dependency\n")
+			body.ret(fmt.Sprintf("%s(/* dependencies */)", constructor))
 		}
 	default:
-		content.WriteString(fmt.Sprintf("\treturn New%s()\n", interfaceName))
+		body.ret(fmt.Sprintf("%s()", constructor))
 	}
 
-	content.WriteString("}\n\n")
+	return body
 }
 
-func (g *Generator) writeWireInjector(content *strings.Builder, interfaceName string, projectInfo *types.ProjectInfo) {
-	content.WriteString(fmt.Sprintf("// Initialize%s creates a fully wired %s instance\n", interfaceName, interfaceName))
-	content.WriteString(fmt.Sprintf("func Initialize%s(db *sql.DB, ctx context.Context, config *Config) (%s, error) {\n", interfaceName, interfaceName))
-	content.WriteString("\twire.Build(ProviderSet)\n")
-	content.WriteString("\treturn nil, nil // Wire will generate the implementation\n")
-	content.WriteString("}\n\n")
+// constructorRef returns the constructor of the interface with the given key
+// as referred to from the package from
+func (g *Generator) constructorRef(key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo, from goPackage) string {
+	if fn := g.handwrittenConstructor(key, projectInfo); fn != nil {
+		return qualifier(g.declPackage(fn.Package, fn.FilePath, projectInfo), from) + fn.Name
+	}
+
+	target := g.implPackage(interfaceInfo, projectInfo)
+	return qualifier(target, from) + g.constructorName(interfaceInfo.Name)
+}
+
+// interfaceRef returns an analyzed interface as referred to from the package from
+func (g *Generator) interfaceRef(interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo, from goPackage) string {
+	source := g.sourcePackage(interfaceInfo, projectInfo)
+	return qualifier(source, from) + interfaceInfo.Name
+}
+
+// diImports returns the imports the factory and wire files need to refer to
+// the generated constructors, the analyzed interfaces and the Config type
+func (g *Generator) diImports(from goPackage, projectInfo *types.ProjectInfo) []string {
+	paths := make(map[string]bool)
+	for _, key := range g.sortedInterfaces(projectInfo) {
+		interfaceInfo := projectInfo.Interfaces[key]
+		constructorPkg := g.implPackage(interfaceInfo, projectInfo)
+		if fn := g.handwrittenConstructor(key, projectInfo); fn != nil {
+			constructorPkg = g.declPackage(fn.Package, fn.FilePath, projectInfo)
+		}
+
+		for _, pkg := range []goPackage{constructorPkg, g.sourcePackage(interfaceInfo, projectInfo)} {
+			if pkg.importPath != from.importPath {
+				paths[pkg.importPath] = true
+			}
+		}
+	}
+
+	if configPkg, _ := g.configPackage(projectInfo); configPkg.importPath != from.importPath {
+		paths[configPkg.importPath] = true
+	}
+	return importSpecs(paths)
 }
 
 // Helper methods
 
-func (g *Generator) generateDependencies(interfaceName string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) []string {
+// generateDependencies returns the "name type" parameters of the generated
+// constructor of the interface with the given key, with types as referred to
+// from the package the interface is declared in
+func (g *Generator) generateDependencies(key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) []string {
 	var deps []string
-	baseName := g.extractBaseName(interfaceName)
+	baseName := g.extractBaseName(interfaceInfo.Name)
+	dir := g.sourcePackage(interfaceInfo, projectInfo).dir
 
 	switch interfaceInfo.Layer {
 	case types.RepositoryLayer:
-		deps = append(deps, "db *sql.DB")
+		if entity := g.repositoryEntity(key, interfaceInfo, projectInfo); entity != nil && entity.mongo {
+			deps = append(deps, "collection *mongo.Collection")
+		} else {
+			deps = append(deps, "db *sql.DB")
+		}
 	case types.UseCaseLayer:
-		repoInterface := g.findRelatedInterface(baseName, types.RepositoryLayer, projectInfo)
+		repoInterface := g.findRelatedInterface(baseName, types.RepositoryLayer, dir, projectInfo)
 		if repoInterface != "" {
-			deps = append(deps, fmt.Sprintf("repo %s", repoInterface))
+			deps = append(deps, fmt.Sprintf("repo %s", g.interfaceDependency(repoInterface, interfaceInfo, projectInfo)))
 		}
 	case types.HandlerLayer:
-		useCaseInterface := g.findRelatedInterface(baseName, types.UseCaseLayer, projectInfo)
+		useCaseInterface := g.findRelatedInterface(baseName, types.UseCaseLayer, dir, projectInfo)
 		if useCaseInterface != "" {
-			deps = append(deps, fmt.Sprintf("useCase %s", useCaseInterface))
+			deps = append(deps, fmt.Sprintf("useCase %s", g.interfaceDependency(useCaseInterface, interfaceInfo, projectInfo)))
 		}
 	}
 
 	return deps
 }
 
-func (g *Generator) findRelatedInterface(baseName string, layer types.LayerType, projectInfo *types.ProjectInfo) string {
+// layerOrder is the order layers appear in generated output, from the
+// innermost dependency outwards
+var layerOrder = map[types.LayerType]int{
+	types.RepositoryLayer: 0,
+	types.ServiceLayer:    1,
+	types.UseCaseLayer:    2,
+	types.HandlerLayer:    3,
+}
+
+// sortedInterfaces returns the keys of the analyzed interfaces sorted by
+// layer, name and package, so generated output does not depend on map
+// iteration order
+func (g *Generator) sortedInterfaces(projectInfo *types.ProjectInfo) []string {
+	keys := make([]string, 0, len(projectInfo.Interfaces))
+	for key := range projectInfo.Interfaces {
+		keys = append(keys, key)
+	}
+
+	rank := func(key string) int {
+		if order, ok := layerOrder[projectInfo.Interfaces[key].Layer]; ok {
+			return order
+		}
+		return len(layerOrder)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if ri, rj := rank(keys[i]), rank(keys[j]); ri != rj {
+			return ri < rj
+		}
+		if ni, nj := projectInfo.Interfaces[keys[i]].Name, projectInfo.Interfaces[keys[j]].Name; ni != nj {
+			return ni < nj
+		}
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
+
+// findRelatedInterface returns the key of the interface of layer named after
+// baseName, preferring one declared in the package in dir
+func (g *Generator) findRelatedInterface(baseName string, layer types.LayerType, dir string, projectInfo *types.ProjectInfo) string {
 	suffixes := map[types.LayerType][]string{
 		types.RepositoryLayer: {"Repo", "Repository"},
 		types.UseCaseLayer:    {"UseCase", "Service"},
@@ -462,32 +762,94 @@ func (g *Generator) findRelatedInterface(baseName string, layer types.LayerType,
 	}
 
 	for _, suffix := range suffixes[layer] {
-		interfaceName := baseName + suffix
-		if _, exists := projectInfo.Interfaces[interfaceName]; exists {
-			return interfaceName
+		if key := types.Key(dir, baseName+suffix); has(projectInfo.Interfaces, key) {
+			return key
+		}
+	}
+	for _, suffix := range suffixes[layer] {
+		if key, exists := lookup(projectInfo.Interfaces, baseName+suffix, typeScope{dir: dir}, projectInfo); exists {
+			return key
 		}
 	}
 
 	return ""
 }
 
-func (g *Generator) generateZeroValue(typeName string) string {
+// numericTypes are the predeclared numeric types
+var numericTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"byte": true, "rune": true, "float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+// wellKnownZeroValues are the zero values of common standard library types
+var wellKnownZeroValues = map[string]string{
+	"context.Context":     "nil",
+	"http.Handler":        "nil",
+	"http.ResponseWriter": "nil",
+	"io.Reader":           "nil",
+	"io.Writer":           "nil",
+	"io.ReadCloser":       "nil",
+	"time.Duration":       "0",
+	"time.Time":           "time.Time{}",
+	"uuid.UUID":           "uuid.UUID{}",
+}
+
+// generateZeroValue returns the zero value of typeName. Named types declared
+// in the project resolve through their underlying type; other named types
+// without a well-known zero value use *new(T), which is valid for any type.
+func (g *Generator) generateZeroValue(typeName string, projectInfo *types.ProjectInfo) string {
 	switch {
-	case typeName == "error":
-		return "nil"
 	case typeName == "string":
 		return `""`
-	case strings.HasPrefix(typeName, "int") || strings.HasPrefix(typeName, "uint") ||
-		typeName == "float32" || typeName == "float64":
-		return "0"
 	case typeName == "bool":
 		return "false"
-	case strings.HasPrefix(typeName, "*") || strings.HasPrefix(typeName, "[]") ||
-		strings.HasPrefix(typeName, "map[") || strings.Contains(typeName, "interface"):
+	case numericTypes[typeName]:
+		return "0"
+	case typeName == "error" || typeName == "any" ||
+		strings.HasPrefix(typeName, "*") || strings.HasPrefix(typeName, "[]") ||
+		strings.HasPrefix(typeName, "map[") || strings.HasPrefix(typeName, "chan ") ||
+		strings.HasPrefix(typeName, "chan<-") ||
+		strings.HasPrefix(typeName, "<-chan") || strings.HasPrefix(typeName, "func(") ||
+		strings.HasPrefix(typeName, "interface{"):
 		return "nil"
-	default:
-		return fmt.Sprintf("%s{}", typeName)
+	case strings.HasPrefix(typeName, "[") || strings.HasPrefix(typeName, "struct{"):
+		// Arrays and struct literals
+		return typeName + "{}"
+	}
+
+	if zero, ok := wellKnownZeroValues[typeName]; ok {
+		return zero
+	}
+
+	// Named types, possibly qualified or instantiated
+	base, _, _ := strings.Cut(typeName, "[")
+	pkg, name, qualified := strings.Cut(base, ".")
+	if !qualified {
+		pkg, name = "", base
 	}
+
+	if len(named(projectInfo.Structs, name, pkg, projectInfo)) > 0 {
+		return typeName + "{}"
+	}
+	if len(named(projectInfo.Interfaces, name, pkg, projectInfo)) > 0 {
+		return "nil"
+	}
+	if keys := named(projectInfo.Types, name, pkg, projectInfo); len(keys) > 0 && projectInfo.Types[keys[0]].Underlying != base {
+		typeInfo := projectInfo.Types[keys[0]]
+		// Untyped constants convert to the named type; composite literals
+		// have to name it
+		switch zero := g.generateZeroValue(typeInfo.Underlying, projectInfo); {
+		case strings.HasSuffix(zero, "{}"):
+			return typeName + "{}"
+		case strings.HasPrefix(zero, "*new("):
+			return fmt.Sprintf("*new(%s)", typeName)
+		default:
+			return zero
+		}
+	}
+
+	return fmt.Sprintf("*new(%s)", typeName)
 }
 
 func (g *Generator) extractBaseName(interfaceName string) string {
@@ -501,3 +863,38 @@ func (g *Generator) extractBaseName(interfaceName string) string {
 
 	return interfaceName
 }
+
+// writeDocComment writes comment lines as a // comment block
+func writeDocComment(content *strings.Builder, indent string, comments []string) {
+	for _, comment := range comments {
+		if comment = strings.TrimSpace(comment); comment == "" {
+			content.WriteString(indent + "//\n")
+		} else {
+			content.WriteString(fmt.Sprintf("%s// %s\n", indent, comment))
+		}
+	}
+}
+
+// usedImports drops the quoted import paths whose package is not referenced
+// outside comments in body
+func usedImports(imports []string, body string) []string {
+	var code strings.Builder
+	for _, line := range strings.Split(body, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "//") {
+			code.WriteString(line + "\n")
+		}
+	}
+
+	var result []string
+	for _, imp := range imports {
+		elems := strings.Split(strings.Trim(imp, "\""), "/")
+		name := elems[len(elems)-1]
+		if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+			name = elems[len(elems)-2]
+		}
+		if strings.Contains(code.String(), name+".") {
+			result = append(result, imp)
+		}
+	}
+	return result
+}
@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/navyarakshakarya/code-gen/analyzer"
+	"github.com/navyarakshakarya/code-gen/logger"
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// analyze writes files, keyed by slash-separated path, into a new project of
+// module example.com/shop and analyzes it
+func analyze(t *testing.T, files map[string]string) *types.ProjectInfo {
+	t.Helper()
+
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/shop\n\ngo 1.24\n"
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	projectInfo, err := analyzer.New(logger.New(false, true), analyzer.Options{}).AnalyzeProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return projectInfo
+}
+
+// generate generates the files of projectInfo, keyed by file name
+func generate(t *testing.T, options Options, projectInfo *types.ProjectInfo) map[string]string {
+	t.Helper()
+
+	results, err := New(logger.New(false, true), options).Generate(projectInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, result := range results {
+		files[result.Filename] = result.Content
+	}
+	return files
+}
+
+// funcSource parses a generated file and returns the source of its function
+// or method called name
+func funcSource(t *testing.T, content, name string) string {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("generated file does not parse: %v\n%s", err, content)
+	}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == name {
+			return content[fset.Position(fn.Pos()).Offset:fset.Position(fn.End()).Offset]
+		}
+	}
+	t.Fatalf("function %s not found in\n%s", name, content)
+	return ""
+}
+
+// typeCheck type-checks files, keyed by name, as one package importing the
+// standard library only
+func typeCheck(t *testing.T, files map[string]string) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for name, content := range files {
+		file, err := parser.ParseFile(fset, name, content, 0)
+		if err != nil {
+			t.Fatalf("%s does not parse: %v\n%s", name, err, content)
+		}
+		parsed = append(parsed, file)
+	}
+
+	config := gotypes.Config{Importer: importer.Default()}
+	if _, err := config.Check("example.com/shop", fset, parsed, nil); err != nil {
+		t.Errorf("generated code does not compile: %v", err)
+	}
+}
+
+// shopSource declares the interfaces of all three layers, with variadic
+// parameters, named results, channels and funcs
+const shopSource = `package shop
+
+import (
+	"context"
+	"net/http"
+)
+
+type User struct {
+	ID    int64
+	Email string
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*User, error)
+	Delete(ctx context.Context, id int64) error
+	Count(ctx context.Context) (int, error)
+}
+
+type UserUseCase interface {
+	Register(ctx context.Context, email string, tags ...string) (user *User, err error)
+	Remove(ctx context.Context, id int64) error
+	Watch(ctx context.Context) (<-chan User, func(), error)
+}
+
+type UserHandler interface {
+	Get(w http.ResponseWriter, r *http.Request)
+}
+`
+
+func TestGenerateIsFormatted(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+	}{
+		{"implementations", Options{}},
+		{"tests and benchmarks", Options{Tests: true, Benchmarks: true}},
+		{"mocks", Options{Mode: ModeMocks}},
+		{"layered", Options{Layout: LayoutLayered, Tests: true}},
+		{"header and style", Options{Header: "// Copyright Example", Style: Style{Receiver: "s", ValueReceivers: true, Comments: CommentsNone}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := generate(t, tt.options, analyze(t, map[string]string{"shop.go": shopSource}))
+			if len(files) == 0 {
+				t.Fatal("no files generated")
+			}
+			for name, content := range files {
+				formatted, err := format.Source([]byte(content))
+				if err != nil {
+					t.Errorf("%s does not parse: %v\n%s", name, err, content)
+					continue
+				}
+				if string(formatted) != content {
+					t.Errorf("%s is not gofmt-clean:\n%s", name, content)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteMethodImplementation(t *testing.T) {
+	files := generate(t, Options{}, analyze(t, map[string]string{"shop.go": shopSource}))
+	content := files["user_usecase.gen.go"]
+
+	tests := []struct {
+		method string
+		want   string
+	}{
+		// Named results are kept and returned with a bare return
+		{"Register", `func (impl *userUseCase) Register(ctx context.Context, email string, tags ...string) (user *User, err error) {
+	// TODO: Implement Register
+	// Example business logic:
+	// 1. Validate input parameters
+	// 2. Call repository methods
+	// 3. Apply business rules
+	// 4. Return processed result
+	return
+}`},
+		{"Watch", `func (impl *userUseCase) Watch(ctx context.Context) (<-chan User, func(), error) {
+	// TODO: Implement Watch
+	// Example business logic:
+	// 1. Validate input parameters
+	// 2. Call repository methods
+	// 3. Apply business rules
+	// 4. Return processed result
+	return nil, nil, nil
+}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := funcSource(t, content, tt.method); got != tt.want {
+				t.Errorf("got:\n%s\n\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+
+	typeCheck(t, map[string]string{
+		"shop.go":                shopSource,
+		"user_repository.gen.go": files["user_repository.gen.go"],
+		"user_usecase.gen.go":    content,
+		"user_handler.gen.go":    files["user_handler.gen.go"],
+	})
+}
@@ -0,0 +1,176 @@
+package generator
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// Layouts accepted by Options.Layout
+const (
+	LayoutFlat    = "flat"
+	LayoutLayered = "layered"
+)
+
+// diPackageKey is the LayerDirs key of the package holding the factory and wire files
+const diPackageKey = "di"
+
+// DefaultLayerDirs are the package directories used by the layered layout,
+// keyed by layer name plus "di" for the factory and wire files
+var DefaultLayerDirs = map[string]string{
+	types.RepositoryLayer.String(): "internal/infrastructure/repository",
+	types.UseCaseLayer.String():    "internal/usecase",
+	types.HandlerLayer.String():    "internal/handler",
+	types.ServiceLayer.String():    "internal/service",
+	diPackageKey:                   "internal/di",
+}
+
+// goPackage describes a package that generated code is written to or refers to
+type goPackage struct {
+	dir        string // relative to the output directory, empty for the root
+	name       string
+	importPath string
+}
+
+// layered reports whether generated files are split into layer packages
+func (g *Generator) layered() bool {
+	return g.options.Layout == LayoutLayered
+}
+
+// rootPackage returns the package at the root of the output directory
+func (g *Generator) rootPackage(projectInfo *types.ProjectInfo) goPackage {
+	return goPackage{
+		name:       projectInfo.PackageName,
+		importPath: g.baseImportPath(projectInfo),
+	}
+}
+
+// layerPackage returns the package generated files for key are written to
+func (g *Generator) layerPackage(key string, projectInfo *types.ProjectInfo) goPackage {
+	if !g.layered() {
+		return g.rootPackage(projectInfo)
+	}
+
+	dir := DefaultLayerDirs[key]
+	if override, ok := g.options.LayerDirs[key]; ok && override != "" {
+		dir = filepath.ToSlash(filepath.Clean(override))
+	}
+
+	return goPackage{
+		dir:        dir,
+		name:       packageNameFromDir(dir),
+		importPath: path.Join(g.baseImportPath(projectInfo), dir),
+	}
+}
+
+// implPackage returns the package the implementation of an analyzed interface
+// is generated in: its layer package, or next to the interface in the flat layout
+func (g *Generator) implPackage(interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) goPackage {
+	if g.layered() {
+		return g.layerPackage(interfaceInfo.Layer.String(), projectInfo)
+	}
+
+	source := g.sourcePackage(interfaceInfo, projectInfo)
+	return goPackage{
+		dir:        source.dir,
+		name:       source.name,
+		importPath: path.Join(g.baseImportPath(projectInfo), source.dir),
+	}
+}
+
+// sourcePackage returns the package an analyzed interface is declared in
+func (g *Generator) sourcePackage(interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) goPackage {
+	return g.declPackage(interfaceInfo.Package, interfaceInfo.FilePath, projectInfo)
+}
+
+// declPackage returns the package of a declaration found in filePath
+func (g *Generator) declPackage(pkgName, filePath string, projectInfo *types.ProjectInfo) goPackage {
+	dir := filepath.ToSlash(filepath.Dir(filePath))
+	if dir == "." {
+		dir = ""
+	}
+	return goPackage{
+		dir:        dir,
+		name:       pkgName,
+		importPath: path.Join(projectInfo.ModuleName, dir),
+	}
+}
+
+// baseImportPath returns the import path of the output directory
+func (g *Generator) baseImportPath(projectInfo *types.ProjectInfo) string {
+	if g.options.BaseImportPath != "" {
+		return g.options.BaseImportPath
+	}
+	return projectInfo.ModuleName
+}
+
+// qualifier returns the prefix used to refer to identifiers of pkg from the package from
+func qualifier(pkg, from goPackage) string {
+	if pkg.importPath == from.importPath {
+		return ""
+	}
+	return pkg.name + "."
+}
+
+// qualifyType prefixes the exported, unqualified identifiers in typeName with prefix
+func qualifyType(typeName, prefix string) string {
+	if prefix == "" {
+		return typeName
+	}
+
+	var result strings.Builder
+	runes := []rune(typeName)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if !isIdentRune(r) {
+			result.WriteRune(r)
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(runes) && isIdentRune(runes[i]) {
+			i++
+		}
+		ident := string(runes[start:i])
+
+		ellipsis := start >= 3 && string(runes[start-3:start]) == "..."
+		selected := start > 0 && runes[start-1] == '.' && !ellipsis
+		selector := i < len(runes) && runes[i] == '.'
+		if unicode.IsUpper(runes[start]) && !selected && !selector {
+			result.WriteString(prefix)
+		}
+		result.WriteString(ident)
+	}
+
+	return result.String()
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// packageNameFromDir derives a package name from the last element of dir
+func packageNameFromDir(dir string) string {
+	name := strings.ToLower(path.Base(dir))
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// importSpecs converts a set of import paths to sorted, quoted import specs
+func importSpecs(paths map[string]bool) []string {
+	var specs []string
+	for importPath := range paths {
+		specs = append(specs, `"`+importPath+`"`)
+	}
+	sort.Strings(specs)
+	return specs
+}
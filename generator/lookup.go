@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// typeScope is where a type name is written: the slash-separated directory of
+// the package, empty or "." for the root, and the imports of the file
+type typeScope struct {
+	dir     string
+	imports map[string]string // package -> import path, nil when unknown
+}
+
+// interfaceScope returns the scope of the types in an interface declaration
+func interfaceScope(interfaceInfo *types.InterfaceInfo) typeScope {
+	return typeScope{dir: path.Dir(interfaceInfo.FilePath), imports: interfaceInfo.Imports}
+}
+
+// funcScope returns the scope of the types in a function signature
+func funcScope(fn *types.FuncInfo) typeScope {
+	return typeScope{dir: path.Dir(fn.FilePath), imports: fn.Imports}
+}
+
+// lookup returns the key in decls of the declaration typeName refers to from
+// scope. Unqualified names resolve to the package of scope, or else to the
+// only declaration of that name in the project; qualified names to the
+// imported project package, or else to the only package of that name
+// declaring one.
+func lookup[T any](decls map[string]T, typeName string, scope typeScope, projectInfo *types.ProjectInfo) (string, bool) {
+	pkg, name, qualified := strings.Cut(typeName, ".")
+	if !qualified {
+		pkg, name = "", typeName
+		if key := types.Key(scope.dir, name); has(decls, key) {
+			return key, true
+		}
+	} else if importPath, imported := scope.imports[pkg]; imported {
+		dir, inProject := strings.CutPrefix(importPath, projectInfo.ModuleName+"/")
+		if importPath == projectInfo.ModuleName {
+			dir, inProject = ".", true
+		}
+		if key := types.Key(dir, name); inProject && has(decls, key) {
+			return key, true
+		}
+		return "", false
+	}
+
+	keys := named(decls, name, pkg, projectInfo)
+	if len(keys) != 1 {
+		return "", false
+	}
+	return keys[0], true
+}
+
+// named returns the sorted keys of the declarations in decls called name, in
+// a package called pkg or in any package when pkg is empty
+func named[T any](decls map[string]T, name, pkg string, projectInfo *types.ProjectInfo) []string {
+	var keys []string
+	for key := range decls {
+		dir, declName := types.SplitKey(key)
+		if declName == name && (pkg == "" || projectInfo.Packages[dir] == pkg) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// has reports whether decls holds key
+func has[T any](decls map[string]T, key string) bool {
+	_, exists := decls[key]
+	return exists
+}
+
+// factoryName returns the name the factory and the Wire injectors build an
+// analyzed interface under: its name, prefixed with its package name when
+// interfaces of other packages share it
+func (g *Generator) factoryName(key string, projectInfo *types.ProjectInfo) string {
+	interfaceInfo := projectInfo.Interfaces[key]
+	if len(named(projectInfo.Interfaces, interfaceInfo.Name, "", projectInfo)) == 1 {
+		return interfaceInfo.Name
+	}
+
+	var prefix strings.Builder
+	for _, part := range strings.Split(interfaceInfo.Package, "_") {
+		if part == "" {
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		prefix.WriteString(string(runes))
+	}
+	return prefix.String() + interfaceInfo.Name
+}
@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/navyarakshakarya/code-gen/logger"
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// twoPackageProject declares Repository in two packages, plus interfaces and
+// structs only one package declares
+func twoPackageProject() *types.ProjectInfo {
+	return &types.ProjectInfo{
+		ModuleName: "example.com/shop",
+		Packages: map[string]string{
+			"internal/user":  "user",
+			"internal/order": "order",
+		},
+		Interfaces: map[string]*types.InterfaceInfo{
+			"internal/user.Repository":  {Name: "Repository", Package: "user", FilePath: "internal/user/user.go", Layer: types.RepositoryLayer},
+			"internal/user.UserUseCase": {Name: "UserUseCase", Package: "user", FilePath: "internal/user/user.go", Layer: types.UseCaseLayer},
+			"internal/order.Repository": {Name: "Repository", Package: "order", FilePath: "internal/order/order.go", Layer: types.RepositoryLayer},
+			"internal/order.OrderRepo":  {Name: "OrderRepo", Package: "order", FilePath: "internal/order/order.go", Layer: types.RepositoryLayer},
+		},
+		Structs: map[string]*types.StructInfo{
+			"internal/order.Order": {Name: "Order", Package: "order", FilePath: "internal/order/order.go"},
+		},
+	}
+}
+
+func TestLookup(t *testing.T) {
+	projectInfo := twoPackageProject()
+	orderImports := map[string]string{"user": "example.com/shop/internal/user"}
+
+	tests := []struct {
+		name     string
+		typeName string
+		scope    typeScope
+		want     string
+	}{
+		{"same package", "Repository", typeScope{dir: "internal/order"}, "internal/order.Repository"},
+		{"ambiguous elsewhere", "Repository", typeScope{dir: "."}, ""},
+		{"unique elsewhere", "OrderRepo", typeScope{dir: "internal/user"}, "internal/order.OrderRepo"},
+		{"imported", "user.Repository", typeScope{dir: "internal/order", imports: orderImports}, "internal/user.Repository"},
+		{"package name", "order.Repository", typeScope{dir: "."}, "internal/order.Repository"},
+		{"external import", "user.Repository", typeScope{dir: ".", imports: map[string]string{"user": "example.com/other/user"}}, ""},
+		{"unknown", "Missing", typeScope{dir: "internal/user"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, exists := lookup(projectInfo.Interfaces, tt.typeName, tt.scope, projectInfo)
+			if key != tt.want || exists != (tt.want != "") {
+				t.Errorf("lookup(%q) = %q, %v, want %q", tt.typeName, key, exists, tt.want)
+			}
+		})
+	}
+}
+
+func TestFactoryName(t *testing.T) {
+	g := New(logger.New(false, true), Options{})
+	projectInfo := twoPackageProject()
+
+	tests := map[string]string{
+		"internal/user.Repository":  "UserRepository",
+		"internal/order.Repository": "OrderRepository",
+		"internal/order.OrderRepo":  "OrderRepo",
+	}
+	for key, want := range tests {
+		if name := g.factoryName(key, projectInfo); name != want {
+			t.Errorf("factoryName(%s) = %s, want %s", key, name, want)
+		}
+	}
+}
+
+func TestFindRelatedInterfacePrefersSamePackage(t *testing.T) {
+	g := New(logger.New(false, true), Options{})
+	projectInfo := twoPackageProject()
+
+	if key := g.findRelatedInterface("", types.RepositoryLayer, "internal/user", projectInfo); key != "internal/user.Repository" {
+		t.Errorf("related repository of internal/user.UseCase = %q, want internal/user.Repository", key)
+	}
+	if key := g.findRelatedInterface("Order", types.RepositoryLayer, "internal/user", projectInfo); key != "internal/order.OrderRepo" {
+		t.Errorf("related repository of internal/user.OrderUseCase = %q, want internal/order.OrderRepo", key)
+	}
+}
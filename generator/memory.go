@@ -0,0 +1,299 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// memoryImports are the packages in-memory repositories may refer to
+var memoryImports = []string{`"database/sql"`, `"go.mongodb.org/mongo-driver/mongo"`, `"sync"`}
+
+// generateMemoryRepository generates an in-memory implementation of a
+// repository interface for the generated benchmarks. Methods that map onto the
+// entity, as the runnable repository bodies do, store and filter a slice of
+// entities; the others return zero values.
+func (g *Generator) generateMemoryRepository(key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) *GeneratedFile {
+	source := g.sourcePackage(interfaceInfo, projectInfo)
+	interfaceName := interfaceInfo.Name
+	target, memoryName, fileName := g.testDouble(key, interfaceInfo, projectInfo, "Memory")
+	prefix := qualifier(source, target)
+	entity := g.repositoryEntity(key, interfaceInfo, projectInfo)
+
+	var body strings.Builder
+
+	body.WriteString(fmt.Sprintf("// %s is an in-memory %s (%s layer)\n", memoryName, interfaceName, interfaceInfo.Layer))
+	body.WriteString(fmt.Sprintf("type %s struct {\n", memoryName))
+	if entity != nil {
+		body.WriteString("\tmu       sync.Mutex\n")
+		body.WriteString(fmt.Sprintf("\tentities []%s\n", qualifyType(entity.name, prefix)))
+	}
+	body.WriteString("}\n\n")
+
+	body.WriteString(fmt.Sprintf("// New%s creates an empty %s\n", memoryName, memoryName))
+	body.WriteString(fmt.Sprintf("func New%s() *%s {\n", memoryName, memoryName))
+	body.WriteString(fmt.Sprintf("\treturn &%s{}\n", memoryName))
+	body.WriteString("}\n\n")
+
+	importPaths := make(map[string]bool)
+	for _, method := range interfaceInfo.Methods {
+		g.writeMemoryMethod(&body, memoryName, interfaceName, method, entity, prefix, projectInfo)
+		g.addSignatureImports(method, g.fileImports(interfaceInfo, projectInfo), importPaths)
+	}
+
+	body.WriteString(fmt.Sprintf("// Ensure %s implements %s\n", memoryName, interfaceName))
+	body.WriteString(fmt.Sprintf("var _ %s = (*%s)(nil)\n", qualifyType(interfaceName, prefix), memoryName))
+
+	if prefix != "" {
+		importPaths[source.importPath] = true
+	}
+	if entity != nil && entity.importPath != "" {
+		importPaths[entity.importPath] = true
+	}
+
+	imports := importSpecs(importPaths)
+	for _, spec := range usedImports(memoryImports, body.String()) {
+		if !importPaths[strings.Trim(spec, `"`)] {
+			imports = append(imports, spec)
+		}
+	}
+
+	var content strings.Builder
+	g.writeFileHeader(&content, target.name)
+	content.WriteString("import (\n")
+	for _, imp := range imports {
+		content.WriteString(fmt.Sprintf("\t%s\n", imp))
+	}
+	content.WriteString(")\n\n")
+	content.WriteString(body.String())
+
+	return &GeneratedFile{
+		Filename:  fileName,
+		Content:   content.String(),
+		LineCount: strings.Count(content.String(), "\n"),
+	}
+}
+
+// generateMemoryRepositories generates the in-memory repositories used by the
+// generated benchmarks
+func (g *Generator) generateMemoryRepositories(memory map[string]bool, projectInfo *types.ProjectInfo) []*GeneratedFile {
+	keys := make([]string, 0, len(memory))
+	for key := range memory {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var results []*GeneratedFile
+	for _, key := range keys {
+		results = append(results, g.generateMemoryRepository(key, projectInfo.Interfaces[key], projectInfo))
+	}
+	return results
+}
+
+// writeMemoryMethod writes a method of an in-memory repository
+func (g *Generator) writeMemoryMethod(content *strings.Builder, memoryName, interfaceName string, method types.MethodInfo, entity *entityInfo, prefix string, projectInfo *types.ProjectInfo) {
+	var params []string
+	for i, param := range method.Params {
+		paramType := qualifyType(param.Type, prefix)
+		switch param.Name {
+		case "":
+			params = append(params, paramType)
+		case "m":
+			// The receiver's name is taken; the method does not map onto
+			// the entity, so the parameter is unused
+			params = append(params, fmt.Sprintf("arg%d %s", i, paramType))
+		default:
+			params = append(params, fmt.Sprintf("%s %s", param.Name, paramType))
+		}
+	}
+
+	// Results are unnamed, so their names cannot clash with the body
+	var returns []string
+	for _, ret := range method.Returns {
+		returns = append(returns, qualifyType(ret.Type, prefix))
+	}
+	unnamed := method
+	unnamed.Returns = make([]types.ParamInfo, len(method.Returns))
+	for i, ret := range method.Returns {
+		unnamed.Returns[i] = types.ParamInfo{Type: ret.Type}
+	}
+
+	content.WriteString(fmt.Sprintf("// %s implements %s.%s in memory\n", method.Name, interfaceName, method.Name))
+	content.WriteString(fmt.Sprintf("func (m *%s) %s(%s)", memoryName, method.Name, strings.Join(params, ", ")))
+	switch len(returns) {
+	case 0:
+		content.WriteString(" {\n")
+	case 1:
+		content.WriteString(fmt.Sprintf(" %s {\n", returns[0]))
+	default:
+		content.WriteString(fmt.Sprintf(" (%s) {\n", strings.Join(returns, ", ")))
+	}
+
+	if entity != nil {
+		if call, ok := g.repositoryCall("m", unnamed, entity); ok {
+			var body strings.Builder
+			if g.writeMemoryBody(&body, unnamed, call, entity, prefix, projectInfo) {
+				content.WriteString(body.String())
+				content.WriteString("}\n\n")
+				return
+			}
+		}
+	}
+
+	if len(returns) > 0 {
+		content.WriteString("\t// Not stored in memory\n")
+		g.writeErrorReturn(content, unnamed, prefix, "\t", "nil", projectInfo)
+	}
+	content.WriteString("}\n\n")
+}
+
+// writeMemoryBody writes the in-memory implementation of a repository method
+// mapped onto the entity, or returns false when the filter parameters cannot
+// be compared with the entity fields
+func (g *Generator) writeMemoryBody(body *strings.Builder, method types.MethodInfo, call *repositoryCall, entity *entityInfo, prefix string, projectInfo *types.ProjectInfo) bool {
+	var conditions []string
+	for i, column := range call.filters {
+		if !comparableField(column, entity) || paramType(method, call.filterArgs[i]) != column.typ {
+			return false
+		}
+		conditions = append(conditions, fmt.Sprintf("stored.%s == %s", column.field, call.filterArgs[i]))
+	}
+	condition := strings.Join(conditions, " && ")
+	if call.op == opUpdate && !comparableField(*entity.id, entity) {
+		return false
+	}
+
+	notFound := "sql.ErrNoRows"
+	if entity.mongo {
+		notFound = "mongo.ErrNoDocuments"
+	}
+
+	// ret returns the successful return statement, indented by indent
+	ret := func(indent, value string) string {
+		var statement strings.Builder
+		g.writeReturn(&statement, method, entity, prefix, value, projectInfo)
+		if statement.Len() == 0 {
+			return ""
+		}
+		return indent + statement.String()
+	}
+
+	var statements strings.Builder
+	switch call.op {
+	case opGet:
+		if !g.returnsEntity(method, entity, false) {
+			return false
+		}
+		statements.WriteString("\tfor _, stored := range m.entities {\n")
+		statements.WriteString(fmt.Sprintf("\t\tif %s {\n", condition))
+		statements.WriteString("\t\t\tentity := stored\n")
+		statements.WriteString(ret("\t\t", "entity"))
+		statements.WriteString("\t\t}\n")
+		statements.WriteString("\t}\n")
+		g.writeErrorReturn(&statements, method, prefix, "\t", notFound, projectInfo)
+
+	case opList:
+		if !g.returnsEntity(method, entity, true) {
+			return false
+		}
+		listType, element := g.listType(method, entity, prefix)
+		statements.WriteString(fmt.Sprintf("\tvar entities %s\n", listType))
+		statements.WriteString("\tfor _, stored := range m.entities {\n")
+		indent := "\t\t"
+		if condition != "" {
+			statements.WriteString(fmt.Sprintf("\t\tif %s {\n", condition))
+			indent = "\t\t\t"
+		}
+		statements.WriteString(indent + "entity := stored\n")
+		statements.WriteString(fmt.Sprintf("%sentities = append(entities, %s)\n", indent, element))
+		if condition != "" {
+			statements.WriteString("\t\t}\n")
+		}
+		statements.WriteString("\t}\n")
+		statements.WriteString(ret("", "entities"))
+
+	case opCreate:
+		statements.WriteString(fmt.Sprintf("\tm.entities = append(m.entities, %s)\n", call.entityParam))
+		statements.WriteString(ret("", call.entityParam))
+
+	case opUpdate:
+		param := strings.TrimPrefix(call.entityParam, "*")
+		statements.WriteString("\tfor i, stored := range m.entities {\n")
+		statements.WriteString(fmt.Sprintf("\t\tif stored.%s == %s.%s {\n", entity.id.field, param, entity.id.field))
+		statements.WriteString(fmt.Sprintf("\t\t\tm.entities[i] = %s\n", call.entityParam))
+		statements.WriteString("\t\t}\n")
+		statements.WriteString("\t}\n")
+		statements.WriteString(ret("", call.entityParam))
+
+	case opDelete:
+		statements.WriteString("\tkept := m.entities[:0]\n")
+		statements.WriteString("\tfor _, stored := range m.entities {\n")
+		kept := "!(" + condition + ")"
+		if len(conditions) == 1 {
+			kept = strings.Replace(condition, " == ", " != ", 1)
+		}
+		statements.WriteString(fmt.Sprintf("\t\tif %s {\n", kept))
+		statements.WriteString("\t\t\tkept = append(kept, stored)\n")
+		statements.WriteString("\t\t}\n")
+		statements.WriteString("\t}\n")
+		statements.WriteString("\tm.entities = kept\n")
+		statements.WriteString(ret("", ""))
+
+	case opCount:
+		countType := g.countType(method)
+		if countType == "" {
+			return false
+		}
+		switch {
+		case condition == "" && countType == "int":
+			statements.WriteString("\tcount := len(m.entities)\n")
+		case condition == "":
+			statements.WriteString(fmt.Sprintf("\tcount := %s(len(m.entities))\n", countType))
+		default:
+			statements.WriteString(fmt.Sprintf("\tvar count %s\n", countType))
+			statements.WriteString("\tfor _, stored := range m.entities {\n")
+			statements.WriteString(fmt.Sprintf("\t\tif %s {\n", condition))
+			statements.WriteString("\t\t\tcount++\n")
+			statements.WriteString("\t\t}\n")
+			statements.WriteString("\t}\n")
+		}
+		statements.WriteString(ret("", "count"))
+	}
+
+	body.WriteString("\tm.mu.Lock()\n")
+	body.WriteString("\tdefer m.mu.Unlock()\n\n")
+	body.WriteString(statements.String())
+	return true
+}
+
+// comparableField reports whether a field of the entity can be compared with
+// == from the package of the repository: its type is not a slice, map or
+// func, and it is not a type of another package the entity refers to
+// unqualified
+func comparableField(column entityColumn, entity *entityInfo) bool {
+	if strings.HasPrefix(column.typ, "[]") || strings.HasPrefix(column.typ, "map[") || strings.HasPrefix(column.typ, "func") {
+		return false
+	}
+	if entity.importPath == "" {
+		return true
+	}
+	for _, r := range column.typ {
+		if unicode.IsUpper(r) {
+			return strings.Contains(column.typ, ".")
+		}
+	}
+	return true
+}
+
+// paramType returns the type of the method parameter called name
+func paramType(method types.MethodInfo, name string) string {
+	for _, param := range method.Params {
+		if param.Name == name {
+			return param.Type
+		}
+	}
+	return ""
+}
@@ -0,0 +1,186 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// Modes accepted by Options.Mode
+const (
+	ModeImplementations = "implementations"
+	ModeMocks           = "mocks"
+)
+
+// mocksDir is the directory mocks are generated into
+const mocksDir = "mocks"
+
+// generateMocks generates a testify mock for every analyzed interface
+func (g *Generator) generateMocks(projectInfo *types.ProjectInfo) ([]*GeneratedFile, error) {
+	var results []*GeneratedFile
+
+	for _, key := range g.sortedInterfaces(projectInfo) {
+		results = append(results, g.generateMock(key, projectInfo.Interfaces[key], projectInfo))
+	}
+
+	return results, nil
+}
+
+// generateMock generates a testify mock for an interface. Mocks live in a
+// separate mocks package, except for interfaces of package main or declared in
+// test files, which cannot be imported and get a _test.go mock next to them
+// instead.
+func (g *Generator) generateMock(key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) *GeneratedFile {
+	source := g.sourcePackage(interfaceInfo, projectInfo)
+	interfaceName := interfaceInfo.Name
+	target, mockName, fileName := g.testDouble(key, interfaceInfo, projectInfo, "Mock")
+	prefix := qualifier(source, target)
+
+	var body strings.Builder
+
+	body.WriteString(fmt.Sprintf("// %s is a testify mock of %s (%s layer)\n", mockName, interfaceName, interfaceInfo.Layer))
+	body.WriteString(fmt.Sprintf("type %s struct {\n", mockName))
+	body.WriteString("\tmock.Mock\n")
+	body.WriteString("}\n\n")
+
+	importPaths := map[string]bool{"github.com/stretchr/testify/mock": true}
+	for _, method := range interfaceInfo.Methods {
+		g.writeMockMethod(&body, mockName, interfaceName, method, prefix)
+		g.addSignatureImports(method, g.fileImports(interfaceInfo, projectInfo), importPaths)
+	}
+
+	body.WriteString(fmt.Sprintf("// Ensure %s implements %s\n", mockName, interfaceName))
+	body.WriteString(fmt.Sprintf("var _ %s = (*%s)(nil)\n", qualifyType(interfaceName, prefix), mockName))
+
+	if prefix != "" {
+		importPaths[source.importPath] = true
+	}
+
+	var content strings.Builder
+	g.writeFileHeader(&content, target.name)
+	content.WriteString("import (\n")
+	for _, imp := range importSpecs(importPaths) {
+		content.WriteString(fmt.Sprintf("\t%s\n", imp))
+	}
+	content.WriteString(")\n\n")
+	content.WriteString(body.String())
+
+	return &GeneratedFile{
+		Filename:  fileName,
+		Content:   content.String(),
+		LineCount: strings.Count(content.String(), "\n"),
+	}
+}
+
+// testDouble returns the package, type name and file name of a test double
+// of an interface, such as its mock: kind followed by the factory name of the
+// interface, in the mocks package. Interfaces of package main or declared in
+// test files cannot be imported, so their test doubles are _test.go files
+// next to them named after the interface alone.
+func (g *Generator) testDouble(key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo, kind string) (goPackage, string, string) {
+	source := g.sourcePackage(interfaceInfo, projectInfo)
+	interfaceName := interfaceInfo.Name
+	baseName := strings.TrimSuffix(g.generateFileName(interfaceName, interfaceInfo.Layer), ".gen.go")
+	suffix := "_" + strings.ToLower(kind)
+
+	if source.name == "main" || strings.HasSuffix(interfaceInfo.FilePath, "_test.go") {
+		return source, kind + interfaceName, path.Join(source.dir, baseName+suffix+"_test.go")
+	}
+
+	target := goPackage{
+		dir:        mocksDir,
+		name:       mocksDir,
+		importPath: path.Join(g.baseImportPath(projectInfo), mocksDir),
+	}
+	name := g.factoryName(key, projectInfo)
+	// Test doubles of interfaces sharing a name are told apart by their
+	// package directory
+	fileName := baseName
+	if name != interfaceName {
+		fileName = strings.ReplaceAll(source.dir, "/", "_") + "_" + baseName
+	}
+	// Mocks were generated first and keep their unsuffixed file names
+	if kind != "Mock" {
+		fileName += suffix
+	}
+	return target, kind + name, path.Join(mocksDir, fileName+".gen.go")
+}
+
+// writeMockMethod writes a mock method recording its call and returning the
+// values configured with On(...).Return(...)
+func (g *Generator) writeMockMethod(content *strings.Builder, mockName, interfaceName string, method types.MethodInfo, prefix string) {
+	var params, args []string
+	for i, param := range method.Params {
+		name := param.Name
+		if name == "" || name == "_" || name == "m" || name == "args" {
+			// Unnamed parameters and names used by the mock body are renamed
+			name = fmt.Sprintf("arg%d", i)
+		}
+		params = append(params, fmt.Sprintf("%s %s", name, qualifyType(param.Type, prefix)))
+		args = append(args, name)
+	}
+
+	var returns []string
+	for _, ret := range method.Returns {
+		returns = append(returns, qualifyType(ret.Type, prefix))
+	}
+
+	content.WriteString(fmt.Sprintf("// %s mocks %s.%s\n", method.Name, interfaceName, method.Name))
+	content.WriteString(fmt.Sprintf("func (m *%s) %s(%s)", mockName, method.Name, strings.Join(params, ", ")))
+	switch len(returns) {
+	case 0:
+		content.WriteString(" {\n")
+	case 1:
+		content.WriteString(fmt.Sprintf(" %s {\n", returns[0]))
+	default:
+		content.WriteString(fmt.Sprintf(" (%s) {\n", strings.Join(returns, ", ")))
+	}
+
+	if len(returns) == 0 {
+		content.WriteString(fmt.Sprintf("\tm.Called(%s)\n", strings.Join(args, ", ")))
+		content.WriteString("}\n\n")
+		return
+	}
+
+	content.WriteString(fmt.Sprintf("\targs := m.Called(%s)\n", strings.Join(args, ", ")))
+
+	var results []string
+	for i, retType := range returns {
+		if retType == "error" {
+			results = append(results, fmt.Sprintf("args.Error(%d)", i))
+			continue
+		}
+
+		result := fmt.Sprintf("r%d", i)
+		content.WriteString(fmt.Sprintf("\tvar %s %s\n", result, retType))
+		content.WriteString(fmt.Sprintf("\tif v := args.Get(%d); v != nil {\n", i))
+		content.WriteString(fmt.Sprintf("\t\t%s = v.(%s)\n", result, retType))
+		content.WriteString("\t}\n")
+		results = append(results, result)
+	}
+
+	content.WriteString(fmt.Sprintf("\treturn %s\n", strings.Join(results, ", ")))
+	content.WriteString("}\n\n")
+}
+
+// addSignatureImports adds the import paths of packages referenced by the
+// method signature, resolved through fileImports (package -> import path)
+func (g *Generator) addSignatureImports(method types.MethodInfo, fileImports map[string]string, importPaths map[string]bool) {
+	for _, param := range append(append([]types.ParamInfo{}, method.Params...), method.Returns...) {
+		for _, match := range packageSelector.FindAllStringSubmatch(param.Type, -1) {
+			if importPath, ok := fileImports[match[1]]; ok {
+				importPaths[importPath] = true
+			}
+		}
+	}
+}
+
+// fileImports returns the imports of the file declaring an analyzed interface
+func (g *Generator) fileImports(interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) map[string]string {
+	if interfaceInfo.Imports != nil {
+		return interfaceInfo.Imports
+	}
+	return projectInfo.Imports
+}
@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateMock(t *testing.T) {
+	files := generate(t, Options{Mode: ModeMocks}, analyze(t, map[string]string{"shop.go": shopSource}))
+
+	for _, name := range []string{"mocks/user_repository.gen.go", "mocks/user_usecase.gen.go", "mocks/user_handler.gen.go"} {
+		if content, exists := files[name]; !exists || !strings.Contains(content, "\npackage mocks\n") {
+			t.Errorf("%s not generated in package mocks", name)
+		}
+	}
+	if _, exists := files["user_usecase.gen.go"]; exists {
+		t.Error("implementations generated in mocks mode")
+	}
+
+	usecase := files["mocks/user_usecase.gen.go"]
+	if !strings.Contains(usecase, "var _ shop.UserUseCase = (*MockUserUseCase)(nil)\n") {
+		t.Errorf("mock is not checked against the interface:\n%s", usecase)
+	}
+
+	tests := []struct {
+		file   string
+		method string
+		want   string
+	}{
+		// Named results are dropped, variadic parameters passed as a slice
+		{"mocks/user_usecase.gen.go", "Register", `func (m *MockUserUseCase) Register(ctx context.Context, email string, tags ...string) (*shop.User, error) {
+	args := m.Called(ctx, email, tags)
+	var r0 *shop.User
+	if v := args.Get(0); v != nil {
+		r0 = v.(*shop.User)
+	}
+	return r0, args.Error(1)
+}`},
+		{"mocks/user_usecase.gen.go", "Remove", `func (m *MockUserUseCase) Remove(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}`},
+		{"mocks/user_usecase.gen.go", "Watch", `func (m *MockUserUseCase) Watch(ctx context.Context) (<-chan shop.User, func(), error) {
+	args := m.Called(ctx)
+	var r0 <-chan shop.User
+	if v := args.Get(0); v != nil {
+		r0 = v.(<-chan shop.User)
+	}
+	var r1 func()
+	if v := args.Get(1); v != nil {
+		r1 = v.(func())
+	}
+	return r0, r1, args.Error(2)
+}`},
+		{"mocks/user_handler.gen.go", "Get", `func (m *MockUserHandler) Get(w http.ResponseWriter, r *http.Request) {
+	m.Called(w, r)
+}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := funcSource(t, files[tt.file], tt.method); got != tt.want {
+				t.Errorf("got:\n%s\n\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateMockRenamesParameters(t *testing.T) {
+	source := `package shop
+
+import "context"
+
+type JobService interface {
+	Run(_ context.Context, m int, args ...string) error
+}
+`
+	files := generate(t, Options{Mode: ModeMocks}, analyze(t, map[string]string{"job.go": source}))
+
+	want := `func (m *MockJobService) Run(arg0 context.Context, arg1 int, arg2 ...string) error {
+	args := m.Called(arg0, arg1, arg2)
+	return args.Error(0)
+}`
+	if got := funcSource(t, files["mocks/job_service.gen.go"], "Run"); got != want {
+		t.Errorf("got:\n%s\n\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateMockInPackageMain(t *testing.T) {
+	source := strings.Replace(shopSource, "package shop", "package main", 1) + "\nfunc main() {}\n"
+	files := generate(t, Options{Mode: ModeMocks}, analyze(t, map[string]string{"main.go": source}))
+
+	mock, exists := files["user_usecase_mock_test.go"]
+	if !exists {
+		t.Fatal("mock of package main not generated next to it")
+	}
+	for _, want := range []string{"package main\n", "func (m *MockUserUseCase) Register(ctx context.Context, email string, tags ...string) (*User, error) {", "var _ UserUseCase = (*MockUserUseCase)(nil)"} {
+		if !strings.Contains(mock, want) {
+			t.Errorf("mock does not contain %q:\n%s", want, mock)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package generator
+
+// ModulePins are the versions of the modules generated code imports, used
+// when the project does not require them already
+var ModulePins = map[string]string{
+	"github.com/google/wire":      "v0.6.0",
+	"github.com/stretchr/testify": "v1.9.0",
+	"go.mongodb.org/mongo-driver": "v1.17.1",
+	"github.com/gofiber/fiber/v2": "v2.52.5",
+	"github.com/gin-gonic/gin":    "v1.10.0",
+	"github.com/labstack/echo/v4": "v4.12.0",
+}
@@ -0,0 +1,580 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// entityInfo describes the struct a repository stores, used to generate
+// runnable SQL or Mongo method bodies
+type entityInfo struct {
+	name       string // qualified when declared outside the repository's package
+	importPath string // set when name is qualified
+	table      string // SQL table or Mongo collection name
+	mongo      bool   // fields carry bson tags
+	columns    []entityColumn
+	id         *entityColumn
+}
+
+// entityColumn maps a struct field to its column or document key
+type entityColumn struct {
+	field  string
+	column string
+	typ    string
+}
+
+// repositoryOp is the kind of operation a repository method performs
+type repositoryOp int
+
+const (
+	opUnknown repositoryOp = iota
+	opGet
+	opList
+	opCreate
+	opUpdate
+	opDelete
+	opCount
+)
+
+// repositoryEntity returns the entity stored by the repository interface with
+// the given key: the struct named after the interface's base name (User for
+// UserRepository), preferably declared in the same package
+func (g *Generator) repositoryEntity(key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) *entityInfo {
+	if interfaceInfo.Layer != types.RepositoryLayer {
+		return nil
+	}
+
+	structKey, exists := lookup(projectInfo.Structs, g.extractBaseName(interfaceInfo.Name), interfaceScope(interfaceInfo), projectInfo)
+	if !exists {
+		return nil
+	}
+	structInfo := projectInfo.Structs[structKey]
+
+	entity := &entityInfo{name: structInfo.Name, table: tableName(structInfo.Name)}
+	if pkg := g.declPackage(structInfo.Package, structInfo.FilePath, projectInfo); pkg.importPath != g.sourcePackage(interfaceInfo, projectInfo).importPath {
+		entity.name = pkg.name + "." + structInfo.Name
+		entity.importPath = pkg.importPath
+	}
+	for _, field := range structInfo.Fields {
+		if _, ok := reflect.StructTag(strings.Trim(field.Tag, "`")).Lookup("bson"); ok {
+			entity.mongo = true
+		}
+	}
+
+	tagKey := "db"
+	if entity.mongo {
+		tagKey = "bson"
+	}
+
+	for _, field := range structInfo.Fields {
+		if field.Embedded || !unicode.IsUpper([]rune(field.Name)[0]) {
+			continue
+		}
+
+		column := snakeCase(field.Name)
+		if entity.mongo {
+			column = strings.ToLower(field.Name)
+		}
+		if tag, ok := reflect.StructTag(strings.Trim(field.Tag, "`")).Lookup(tagKey); ok {
+			name := strings.Split(tag, ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name != "" {
+				column = name
+			}
+		}
+
+		entity.columns = append(entity.columns, entityColumn{field: field.Name, column: column, typ: field.Type})
+	}
+
+	for i, column := range entity.columns {
+		if strings.EqualFold(column.field, "ID") || column.column == "_id" {
+			entity.id = &entity.columns[i]
+			break
+		}
+	}
+
+	if len(entity.columns) == 0 {
+		return nil
+	}
+	return entity
+}
+
+// repositoryOperation classifies a repository method by its name and results
+func (g *Generator) repositoryOperation(method types.MethodInfo, entity *entityInfo) repositoryOp {
+	name := strings.ToLower(method.Name)
+	hasPrefix := func(prefixes ...string) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	returnsList := false
+	for _, ret := range method.Returns {
+		if strings.HasPrefix(ret.Type, "[]") && strings.TrimPrefix(strings.TrimPrefix(ret.Type, "[]"), "*") == entity.name {
+			returnsList = true
+		}
+	}
+
+	switch {
+	case hasPrefix("count"):
+		return opCount
+	case hasPrefix("list", "all", "search", "query") || (returnsList && hasPrefix("get", "find", "fetch", "load")):
+		return opList
+	case hasPrefix("get", "find", "fetch", "load", "read"):
+		return opGet
+	case hasPrefix("create", "insert", "save", "add", "store"):
+		return opCreate
+	case hasPrefix("update", "modify", "edit"):
+		return opUpdate
+	case hasPrefix("delete", "remove"):
+		return opDelete
+	}
+	return opUnknown
+}
+
+// repositoryLocals are the identifiers declared by generated repository
+// bodies, including the in-memory ones
+var repositoryLocals = map[string]bool{
+	"query": true, "entity": true, "entities": true, "stored": true,
+	"rows": true, "cursor": true, "count": true, "n": true, "kept": true, "i": true,
+}
+
+// repositoryCall is a repository method mapped onto its entity
+type repositoryCall struct {
+	op          repositoryOp
+	ctx         string
+	entityParam string // entity parameter of create and update, prefixed with * when a pointer
+	filters     []entityColumn
+	filterArgs  []string // parameters compared with filters
+}
+
+// repositoryCall maps a repository method onto the entity, or returns false
+// when its name, parameters or results do not map onto it
+func (g *Generator) repositoryCall(recv string, method types.MethodInfo, entity *entityInfo) (*repositoryCall, bool) {
+	// Names declared by the generated bodies must not clash with the
+	// receiver, parameters or named results of the method
+	if repositoryLocals[recv] {
+		return nil, false
+	}
+	for _, ret := range method.Returns {
+		if repositoryLocals[ret.Name] || ret.Name == recv {
+			return nil, false
+		}
+	}
+
+	call := &repositoryCall{}
+	var params []types.ParamInfo
+	for _, param := range method.Params {
+		if param.Name == "" || param.Name == "_" || param.Name == recv || repositoryLocals[param.Name] {
+			return nil, false
+		}
+		if strings.HasPrefix(param.Type, "...") {
+			// Variadic parameters do not map onto a single column
+			return nil, false
+		}
+		if param.Type == "context.Context" {
+			call.ctx = param.Name
+			continue
+		}
+		params = append(params, param)
+	}
+	if call.ctx == "" {
+		return nil, false
+	}
+
+	call.op = g.repositoryOperation(method, entity)
+
+	// Create and update take the entity; the other operations filter by the
+	// remaining parameters
+	switch call.op {
+	case opUnknown:
+		return nil, false
+	case opCreate, opUpdate:
+		for _, param := range params {
+			if strings.TrimPrefix(param.Type, "*") == entity.name {
+				call.entityParam = param.Name
+				if strings.HasPrefix(param.Type, "*") {
+					call.entityParam = "*" + param.Name
+				}
+			}
+		}
+		if call.entityParam == "" || (call.op == opUpdate && entity.id == nil) {
+			return nil, false
+		}
+	default:
+		for _, param := range params {
+			column := entity.columnFor(param.Name)
+			if column == nil {
+				return nil, false
+			}
+			call.filters = append(call.filters, *column)
+			call.filterArgs = append(call.filterArgs, param.Name)
+		}
+		if (call.op == opGet || call.op == opDelete) && len(call.filters) == 0 {
+			return nil, false
+		}
+	}
+	return call, true
+}
+
+// repositoryMethodBody returns a runnable body for a repository method, or
+// false when the method cannot be mapped onto the entity
+func (g *Generator) repositoryMethodBody(recv string, method types.MethodInfo, entity *entityInfo, prefix string, projectInfo *types.ProjectInfo) (string, bool) {
+	call, ok := g.repositoryCall(recv, method, entity)
+	if !ok {
+		return "", false
+	}
+
+	var body strings.Builder
+	if entity.mongo {
+		ok = g.writeMongoBody(&body, recv, method, call.op, entity, call.ctx, call.entityParam, call.filters, call.filterArgs, prefix, projectInfo)
+	} else {
+		ok = g.writeSQLBody(&body, recv, method, call.op, entity, call.ctx, call.entityParam, call.filters, call.filterArgs, prefix, projectInfo)
+	}
+	return body.String(), ok
+}
+
+// writeSQLBody writes a parameterized database/sql implementation of op
+func (g *Generator) writeSQLBody(body *strings.Builder, recv string, method types.MethodInfo, op repositoryOp, entity *entityInfo, ctx, entityParam string, filters []entityColumn, filterArgs []string, prefix string, projectInfo *types.ProjectInfo) bool {
+	entityType := qualifyType(entity.name, prefix)
+	param := strings.TrimPrefix(entityParam, "*")
+
+	var where []string
+	for i, column := range filters {
+		where = append(where, fmt.Sprintf("%s = $%d", column.column, i+1))
+	}
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = " WHERE " + strings.Join(where, " AND ")
+	}
+	queryArgs := strings.Join(append([]string{ctx, "query"}, filterArgs...), ", ")
+
+	var columns, scans []string
+	for _, column := range entity.columns {
+		columns = append(columns, column.column)
+		scans = append(scans, "&entity."+column.field)
+	}
+
+	switch op {
+	case opGet:
+		if !g.returnsEntity(method, entity, false) {
+			return false
+		}
+		body.WriteString(fmt.Sprintf("\tquery := %q\n", fmt.Sprintf("SELECT %s FROM %s%s", strings.Join(columns, ", "), entity.table, whereClause)))
+		body.WriteString(fmt.Sprintf("\tvar entity %s\n", entityType))
+		body.WriteString(fmt.Sprintf("\tif err := %s.db.QueryRowContext(%s).Scan(%s); err != nil {\n", recv, queryArgs, strings.Join(scans, ", ")))
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("query "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		g.writeReturn(body, method, entity, prefix, "entity", projectInfo)
+
+	case opList:
+		if !g.returnsEntity(method, entity, true) {
+			return false
+		}
+		body.WriteString(fmt.Sprintf("\tquery := %q\n", fmt.Sprintf("SELECT %s FROM %s%s", strings.Join(columns, ", "), entity.table, whereClause)))
+		body.WriteString(fmt.Sprintf("\trows, err := %s.db.QueryContext(%s)\n", recv, queryArgs))
+		body.WriteString("\tif err != nil {\n")
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("query "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		body.WriteString("\tdefer rows.Close()\n\n")
+		listType, element := g.listType(method, entity, prefix)
+		body.WriteString(fmt.Sprintf("\tvar entities %s\n", listType))
+		body.WriteString("\tfor rows.Next() {\n")
+		body.WriteString(fmt.Sprintf("\t\tvar entity %s\n", entityType))
+		body.WriteString(fmt.Sprintf("\t\tif err := rows.Scan(%s); err != nil {\n", strings.Join(scans, ", ")))
+		g.writeErrorReturn(body, method, prefix, "\t\t\t", g.wrapError("scan "+entity.table), projectInfo)
+		body.WriteString("\t\t}\n")
+		body.WriteString(fmt.Sprintf("\t\tentities = append(entities, %s)\n", element))
+		body.WriteString("\t}\n")
+		body.WriteString("\tif err := rows.Err(); err != nil {\n")
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("query "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		g.writeReturn(body, method, entity, prefix, "entities", projectInfo)
+
+	case opCreate:
+		var insertColumns, placeholders, values []string
+		for _, column := range entity.columns {
+			if entity.id != nil && column.field == entity.id.field && isIntegerType(column.typ) {
+				// Integer IDs are assigned by the database
+				continue
+			}
+			insertColumns = append(insertColumns, column.column)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(placeholders)+1))
+			values = append(values, param+"."+column.field)
+		}
+		body.WriteString(fmt.Sprintf("\tquery := %q\n", fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", entity.table, strings.Join(insertColumns, ", "), strings.Join(placeholders, ", "))))
+		body.WriteString(fmt.Sprintf("\tif _, err := %s.db.ExecContext(%s); err != nil {\n", recv, strings.Join(append([]string{ctx, "query"}, values...), ", ")))
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("insert into "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		g.writeReturn(body, method, entity, prefix, entityParam, projectInfo)
+
+	case opUpdate:
+		var sets, values []string
+		for _, column := range entity.columns {
+			if column.field == entity.id.field {
+				continue
+			}
+			sets = append(sets, fmt.Sprintf("%s = $%d", column.column, len(sets)+1))
+			values = append(values, param+"."+column.field)
+		}
+		if len(sets) == 0 {
+			return false
+		}
+		values = append(values, param+"."+entity.id.field)
+		body.WriteString(fmt.Sprintf("\tquery := %q\n", fmt.Sprintf("UPDATE %s SET %s WHERE %s = $%d", entity.table, strings.Join(sets, ", "), entity.id.column, len(values))))
+		body.WriteString(fmt.Sprintf("\tif _, err := %s.db.ExecContext(%s); err != nil {\n", recv, strings.Join(append([]string{ctx, "query"}, values...), ", ")))
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("update "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		g.writeReturn(body, method, entity, prefix, entityParam, projectInfo)
+
+	case opDelete:
+		body.WriteString(fmt.Sprintf("\tquery := %q\n", fmt.Sprintf("DELETE FROM %s%s", entity.table, whereClause)))
+		body.WriteString(fmt.Sprintf("\tif _, err := %s.db.ExecContext(%s); err != nil {\n", recv, queryArgs))
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("delete from "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		g.writeReturn(body, method, entity, prefix, "", projectInfo)
+
+	case opCount:
+		countType := g.countType(method)
+		if countType == "" {
+			return false
+		}
+		body.WriteString(fmt.Sprintf("\tquery := %q\n", fmt.Sprintf("SELECT COUNT(*) FROM %s%s", entity.table, whereClause)))
+		body.WriteString(fmt.Sprintf("\tvar count %s\n", countType))
+		body.WriteString(fmt.Sprintf("\tif err := %s.db.QueryRowContext(%s).Scan(&count); err != nil {\n", recv, queryArgs))
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("count "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		g.writeReturn(body, method, entity, prefix, "count", projectInfo)
+	}
+
+	return true
+}
+
+// writeMongoBody writes a mongo-driver implementation of op using bson filters
+func (g *Generator) writeMongoBody(body *strings.Builder, recv string, method types.MethodInfo, op repositoryOp, entity *entityInfo, ctx, entityParam string, filters []entityColumn, filterArgs []string, prefix string, projectInfo *types.ProjectInfo) bool {
+	entityType := qualifyType(entity.name, prefix)
+	param := strings.TrimPrefix(entityParam, "*")
+
+	var conditions []string
+	for i, column := range filters {
+		conditions = append(conditions, fmt.Sprintf("%q: %s", column.column, filterArgs[i]))
+	}
+	filter := fmt.Sprintf("bson.M{%s}", strings.Join(conditions, ", "))
+
+	switch op {
+	case opGet:
+		if !g.returnsEntity(method, entity, false) {
+			return false
+		}
+		body.WriteString(fmt.Sprintf("\tvar entity %s\n", entityType))
+		body.WriteString(fmt.Sprintf("\tif err := %s.collection.FindOne(%s, %s).Decode(&entity); err != nil {\n", recv, ctx, filter))
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("find "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		g.writeReturn(body, method, entity, prefix, "entity", projectInfo)
+
+	case opList:
+		if !g.returnsEntity(method, entity, true) {
+			return false
+		}
+		body.WriteString(fmt.Sprintf("\tcursor, err := %s.collection.Find(%s, %s)\n", recv, ctx, filter))
+		body.WriteString("\tif err != nil {\n")
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("find "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		body.WriteString(fmt.Sprintf("\tdefer cursor.Close(%s)\n\n", ctx))
+		listType, _ := g.listType(method, entity, prefix)
+		body.WriteString(fmt.Sprintf("\tvar entities %s\n", listType))
+		body.WriteString(fmt.Sprintf("\tif err := cursor.All(%s, &entities); err != nil {\n", ctx))
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("decode "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		g.writeReturn(body, method, entity, prefix, "entities", projectInfo)
+
+	case opCreate:
+		body.WriteString(fmt.Sprintf("\tif _, err := %s.collection.InsertOne(%s, %s); err != nil {\n", recv, ctx, param))
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("insert into "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		g.writeReturn(body, method, entity, prefix, entityParam, projectInfo)
+
+	case opUpdate:
+		idFilter := fmt.Sprintf("bson.M{%q: %s.%s}", entity.id.column, param, entity.id.field)
+		body.WriteString(fmt.Sprintf("\tif _, err := %s.collection.ReplaceOne(%s, %s, %s); err != nil {\n", recv, ctx, idFilter, param))
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("update "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		g.writeReturn(body, method, entity, prefix, entityParam, projectInfo)
+
+	case opDelete:
+		body.WriteString(fmt.Sprintf("\tif _, err := %s.collection.DeleteOne(%s, %s); err != nil {\n", recv, ctx, filter))
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("delete from "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		g.writeReturn(body, method, entity, prefix, "", projectInfo)
+
+	case opCount:
+		countType := g.countType(method)
+		if countType == "" {
+			return false
+		}
+		body.WriteString(fmt.Sprintf("\tn, err := %s.collection.CountDocuments(%s, %s)\n", recv, ctx, filter))
+		body.WriteString("\tif err != nil {\n")
+		g.writeErrorReturn(body, method, prefix, "\t\t", g.wrapError("count "+entity.table), projectInfo)
+		body.WriteString("\t}\n")
+		body.WriteString(fmt.Sprintf("\tcount := %s(n)\n", countType))
+		g.writeReturn(body, method, entity, prefix, "count", projectInfo)
+	}
+
+	return true
+}
+
+// columnFor returns the column a parameter filters on, matching its name
+// against the entity fields (userID and id both match ID), or nil when no
+// field matches
+func (e *entityInfo) columnFor(paramName string) *entityColumn {
+	entityName := e.name[strings.LastIndex(e.name, ".")+1:]
+	name := strings.ToLower(paramName)
+	trimmed := strings.TrimPrefix(name, strings.ToLower(entityName))
+
+	for i, column := range e.columns {
+		field := strings.ToLower(column.field)
+		if field == name || (trimmed != "" && field == trimmed) {
+			return &e.columns[i]
+		}
+	}
+	return nil
+}
+
+// returnsEntity reports whether the method returns the entity (or a slice of it for lists)
+func (g *Generator) returnsEntity(method types.MethodInfo, entity *entityInfo, list bool) bool {
+	for _, ret := range method.Returns {
+		typ := ret.Type
+		if list {
+			if !strings.HasPrefix(typ, "[]") {
+				continue
+			}
+			typ = strings.TrimPrefix(typ, "[]")
+		}
+		if strings.TrimPrefix(typ, "*") == entity.name {
+			return true
+		}
+	}
+	return false
+}
+
+// listType returns the slice type returned by a list method and the
+// expression appending a scanned entity to it
+func (g *Generator) listType(method types.MethodInfo, entity *entityInfo, prefix string) (string, string) {
+	for _, ret := range method.Returns {
+		if ret.Type == "[]*"+entity.name {
+			return qualifyType(ret.Type, prefix), "&entity"
+		}
+	}
+	return "[]" + qualifyType(entity.name, prefix), "entity"
+}
+
+// countType returns the integer result type of a count method
+func (g *Generator) countType(method types.MethodInfo) string {
+	for _, ret := range method.Returns {
+		if isIntegerType(ret.Type) {
+			return ret.Type
+		}
+	}
+	return ""
+}
+
+// writeErrorReturn writes the return statement of a failed database call,
+// returning errValue for the error result
+func (g *Generator) writeErrorReturn(body *strings.Builder, method types.MethodInfo, prefix, indent, errValue string, projectInfo *types.ProjectInfo) {
+	var values []string
+	for _, ret := range method.Returns {
+		if ret.Type == "error" {
+			values = append(values, errValue)
+		} else {
+			values = append(values, g.generateZeroValue(qualifyType(ret.Type, prefix), projectInfo))
+		}
+	}
+	if len(values) == 0 {
+		body.WriteString(indent + "return\n")
+		return
+	}
+	body.WriteString(fmt.Sprintf("%sreturn %s\n", indent, strings.Join(values, ", ")))
+}
+
+// writeReturn writes the successful return statement, returning value for
+// results of the entity, list or count type. Entity parameters of create and
+// update methods are passed as value and may be pointers themselves.
+func (g *Generator) writeReturn(body *strings.Builder, method types.MethodInfo, entity *entityInfo, prefix, value string, projectInfo *types.ProjectInfo) {
+	valuePtr := strings.HasPrefix(value, "*")
+	value = strings.TrimPrefix(value, "*")
+
+	var values []string
+	for _, ret := range method.Returns {
+		base := strings.TrimPrefix(strings.TrimPrefix(ret.Type, "[]"), "*")
+		switch {
+		case ret.Type == "error":
+			values = append(values, "nil")
+		case value != "" && base == entity.name:
+			values = append(values, adaptPointer(ret.Type, value, valuePtr))
+		case value == "count" && isIntegerType(ret.Type):
+			values = append(values, value)
+		default:
+			values = append(values, g.generateZeroValue(qualifyType(ret.Type, prefix), projectInfo))
+		}
+	}
+	if len(values) > 0 {
+		body.WriteString(fmt.Sprintf("\treturn %s\n", strings.Join(values, ", ")))
+	}
+}
+
+// adaptPointer converts value, a pointer when valuePtr is set, to the pointer
+// or value form of retType
+func adaptPointer(retType, value string, valuePtr bool) string {
+	retPtr := strings.HasPrefix(retType, "*")
+	switch {
+	case strings.HasPrefix(retType, "[]") || retPtr == valuePtr:
+		return value
+	case retPtr:
+		return "&" + value
+	default:
+		return "*" + value
+	}
+}
+
+// isIntegerType reports whether typeName is a builtin integer type
+func isIntegerType(typeName string) bool {
+	return strings.HasPrefix(typeName, "int") || strings.HasPrefix(typeName, "uint")
+}
+
+// tableName returns the snake_case plural table name of an entity
+func tableName(entityName string) string {
+	name := snakeCase(entityName)
+	switch {
+	case strings.HasSuffix(name, "y") && !strings.HasSuffix(name, "ay") && !strings.HasSuffix(name, "ey") && !strings.HasSuffix(name, "oy"):
+		return strings.TrimSuffix(name, "y") + "ies"
+	case strings.HasSuffix(name, "s") || strings.HasSuffix(name, "x") || strings.HasSuffix(name, "ch") || strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
+
+// snakeCase converts a Go identifier to snake_case, keeping initialisms together (UserID -> user_id)
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var result strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				result.WriteRune('_')
+			}
+			result.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
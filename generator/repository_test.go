@@ -0,0 +1,179 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const userRepositorySource = `package shop
+
+import "context"
+
+type User struct {
+	ID    int64
+	Email string
+	Name  string
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*User, error)
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindByAddress(ctx context.Context, address string) (*User, error)
+	ListByName(ctx context.Context, name string) ([]User, error)
+	ListRecent(ctx context.Context, limit int) ([]*User, error)
+	Create(ctx context.Context, user *User) error
+	Update(ctx context.Context, user User) error
+	Delete(ctx context.Context, userID int64) error
+	DeleteOlderThan(ctx context.Context, days int) error
+	Count(ctx context.Context) (int, error)
+}
+`
+
+func TestRepositoryMethodBodiesSQL(t *testing.T) {
+	files := generate(t, Options{}, analyze(t, map[string]string{"user.go": userRepositorySource}))
+	content := files["user_repository.gen.go"]
+
+	tests := []struct {
+		method string
+		want   []string // empty when the method keeps the TODO stub
+	}{
+		{"GetByID", []string{
+			`query := "SELECT id, email, name FROM users WHERE id = $1"`,
+			"impl.db.QueryRowContext(ctx, query, id).Scan(&entity.ID, &entity.Email, &entity.Name)",
+			"return &entity, nil",
+		}},
+		{"FindByEmail", []string{
+			`query := "SELECT id, email, name FROM users WHERE email = $1"`,
+			"impl.db.QueryRowContext(ctx, query, email)",
+		}},
+		{"FindByAddress", nil},
+		{"ListByName", []string{
+			`query := "SELECT id, email, name FROM users WHERE name = $1"`,
+			"rows, err := impl.db.QueryContext(ctx, query, name)",
+			"var entities []User",
+			"entities = append(entities, entity)",
+		}},
+		{"ListRecent", nil},
+		{"Create", []string{
+			`query := "INSERT INTO users (email, name) VALUES ($1, $2)"`,
+			"impl.db.ExecContext(ctx, query, user.Email, user.Name)",
+		}},
+		{"Update", []string{
+			`query := "UPDATE users SET email = $1, name = $2 WHERE id = $3"`,
+			"impl.db.ExecContext(ctx, query, user.Email, user.Name, user.ID)",
+		}},
+		{"Delete", []string{
+			`query := "DELETE FROM users WHERE id = $1"`,
+			"impl.db.ExecContext(ctx, query, userID)",
+		}},
+		{"DeleteOlderThan", nil},
+		{"Count", []string{
+			`query := "SELECT COUNT(*) FROM users"`,
+			"var count int",
+			"return count, nil",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			source := funcSource(t, content, tt.method)
+			if len(tt.want) == 0 {
+				if !strings.Contains(source, "// TODO: Implement "+tt.method) || strings.Contains(source, "\tquery :=") {
+					t.Errorf("want the TODO stub for an unmatched parameter, got:\n%s", source)
+				}
+				return
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(source, want) {
+					t.Errorf("body does not contain %s\n\ngot:\n%s", want, source)
+				}
+			}
+		})
+	}
+}
+
+func TestRepositoryMethodBodiesMongo(t *testing.T) {
+	files := generate(t, Options{}, analyze(t, map[string]string{"order.go": `package shop
+
+import "context"
+
+type Order struct {
+	ID     string ` + "`bson:\"_id\"`" + `
+	Status string ` + "`bson:\"status\"`" + `
+}
+
+type OrderRepository interface {
+	GetByID(ctx context.Context, orderID string) (*Order, error)
+	FindByCustomer(ctx context.Context, customer string) (*Order, error)
+	ListByStatus(ctx context.Context, status string) ([]Order, error)
+	Remove(ctx context.Context, id string) error
+	RemoveExpired(ctx context.Context, before string) error
+}
+`}))
+	content := files["order_repository.gen.go"]
+
+	tests := []struct {
+		method string
+		want   []string // empty when the method keeps the TODO stub
+	}{
+		{"GetByID", []string{`impl.collection.FindOne(ctx, bson.M{"_id": orderID}).Decode(&entity)`}},
+		{"FindByCustomer", nil},
+		{"ListByStatus", []string{
+			`cursor, err := impl.collection.Find(ctx, bson.M{"status": status})`,
+			"cursor.All(ctx, &entities)",
+		}},
+		{"Remove", []string{`impl.collection.DeleteOne(ctx, bson.M{"_id": id})`}},
+		{"RemoveExpired", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			source := funcSource(t, content, tt.method)
+			if len(tt.want) == 0 {
+				if !strings.Contains(source, "// TODO: Implement "+tt.method) || strings.Contains(source, "bson.M") {
+					t.Errorf("want the TODO stub for an unmatched parameter, got:\n%s", source)
+				}
+				return
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(source, want) {
+					t.Errorf("body does not contain %s\n\ngot:\n%s", want, source)
+				}
+			}
+		})
+	}
+}
+
+func TestColumnFor(t *testing.T) {
+	entity := &entityInfo{
+		name: "user.User",
+		columns: []entityColumn{
+			{field: "ID", column: "id"},
+			{field: "Email", column: "email_address"},
+		},
+	}
+	entity.id = &entity.columns[0]
+
+	tests := []struct {
+		param string
+		want  string // column, empty for no match
+	}{
+		{"id", "id"},
+		{"userID", "id"},
+		{"UserId", "id"},
+		{"email", "email_address"},
+		{"userEmail", "email_address"},
+		{"address", ""},
+		{"limit", ""},
+		{"user", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.param, func(t *testing.T) {
+			got := ""
+			if column := entity.columnFor(tt.param); column != nil {
+				got = column.column
+			}
+			if got != tt.want {
+				t.Errorf("columnFor(%q) = %q, want %q", tt.param, got, tt.want)
+			}
+		})
+	}
+}
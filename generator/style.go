@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// Constructor naming styles accepted by Style.Constructor
+const (
+	ConstructorInterface = "interface" // New<Interface>
+	ConstructorShort     = "short"     // New, one implementation per package
+)
+
+// Error wrapping styles accepted by Style.ErrorWrapping
+const (
+	ErrorsFmt  = "fmt"  // fmt.Errorf("...: %w", err)
+	ErrorsJoin = "join" // errors.Join(errors.New("..."), err)
+)
+
+// Comment densities accepted by Style.Comments
+const (
+	CommentsFull    = "full"    // doc comments, TODOs and example templates
+	CommentsMinimal = "minimal" // doc comments and TODOs
+	CommentsNone    = "none"    // TODOs only
+)
+
+// ReceiverShort selects the lowercased first letter of the implementation as
+// receiver name
+const ReceiverShort = "short"
+
+// defaultReceiver is the receiver name of generated methods
+const defaultReceiver = "impl"
+
+// Style controls the naming and formatting of generated implementations. Zero
+// values select the defaults. Mocks keep pointer receivers as testify requires.
+type Style struct {
+	Receiver       string // receiver name: "impl" (default), ReceiverShort or any identifier
+	Constructor    string // ConstructorInterface (default) or ConstructorShort
+	ValueReceivers bool   // declare methods on values instead of pointers
+	ErrorWrapping  string // ErrorsFmt (default) or ErrorsJoin
+	Comments       string // CommentsFull (default), CommentsMinimal or CommentsNone
+}
+
+// Validate reports style options with unknown values
+func (s Style) Validate() error {
+	if s.Receiver != "" && s.Receiver != ReceiverShort && (!token.IsIdentifier(s.Receiver) || s.Receiver == "_") {
+		return fmt.Errorf("invalid receiver name %q (expected an identifier or %s)", s.Receiver, ReceiverShort)
+	}
+	if s.Constructor != "" && s.Constructor != ConstructorInterface && s.Constructor != ConstructorShort {
+		return fmt.Errorf("unknown constructor style %q (expected %s or %s)", s.Constructor, ConstructorInterface, ConstructorShort)
+	}
+	if s.ErrorWrapping != "" && s.ErrorWrapping != ErrorsFmt && s.ErrorWrapping != ErrorsJoin {
+		return fmt.Errorf("unknown error wrapping %q (expected %s or %s)", s.ErrorWrapping, ErrorsFmt, ErrorsJoin)
+	}
+	if s.Comments != "" && s.Comments != CommentsFull && s.Comments != CommentsMinimal && s.Comments != CommentsNone {
+		return fmt.Errorf("unknown comment density %q (expected %s, %s or %s)", s.Comments, CommentsFull, CommentsMinimal, CommentsNone)
+	}
+	return nil
+}
+
+// receiver returns the receiver name of the methods of structName
+func (g *Generator) receiver(structName string) string {
+	switch g.options.Style.Receiver {
+	case "":
+		return defaultReceiver
+	case ReceiverShort:
+		return string(unicode.ToLower([]rune(structName)[0]))
+	default:
+		return g.options.Style.Receiver
+	}
+}
+
+// receiverType returns the receiver type of the methods of structName
+func (g *Generator) receiverType(structName string) string {
+	if g.options.Style.ValueReceivers {
+		return structName
+	}
+	return "*" + structName
+}
+
+// constructorName returns the name of the generated constructor of interfaceName
+func (g *Generator) constructorName(interfaceName string) string {
+	if g.options.Style.Constructor == ConstructorShort {
+		return "New"
+	}
+	return "New" + interfaceName
+}
+
+// wrapError returns an expression wrapping err with a message describing the
+// failed action
+func (g *Generator) wrapError(action string) string {
+	if g.options.Style.ErrorWrapping == ErrorsJoin {
+		return fmt.Sprintf("errors.Join(errors.New(\"failed to %s\"), err)", action)
+	}
+	return fmt.Sprintf("fmt.Errorf(\"failed to %s: %%w\", err)", action)
+}
+
+// directiveComment matches directive comments such as //go:build
+var directiveComment = regexp.MustCompile(`^//[a-z0-9]+:[a-z0-9]`)
+
+// applyCommentStyle drops the comments of content the configured density
+// excludes. The file header, directives and TODO markers are always kept.
+func (g *Generator) applyCommentStyle(content string) string {
+	density := g.options.Style.Comments
+	if density == "" || density == CommentsFull {
+		return content
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	var result strings.Builder
+	inHeader := true
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "package ") {
+			inHeader = false
+		}
+
+		if !inHeader && strings.HasPrefix(trimmed, "//") && !directiveComment.MatchString(trimmed) && !strings.Contains(trimmed, "TODO") {
+			// Top-level lines are doc comments, indented ones explain the body
+			docComment := !strings.HasPrefix(line, "\t")
+			if density == CommentsNone || !docComment {
+				continue
+			}
+		}
+		result.WriteString(line)
+	}
+	return result.String()
+}
+
+// styleIssues reports the naming problems introduced by the configured style
+func (g *Generator) styleIssues(projectInfo *types.ProjectInfo) []string {
+	var issues []string
+
+	constructors := make(map[string][]string) // implementation package -> interfaces
+	for _, key := range g.sortedInterfaces(projectInfo) {
+		interfaceInfo := projectInfo.Interfaces[key]
+		if g.handwrittenConstructor(key, projectInfo) != nil {
+			continue
+		}
+
+		target := g.implPackage(interfaceInfo, projectInfo)
+		constructors[target.dir] = append(constructors[target.dir], key)
+
+		receiver := g.receiver(g.generateStructName(interfaceInfo.Name))
+		for _, method := range interfaceInfo.Methods {
+			for _, param := range append(append([]types.ParamInfo{}, method.Params...), method.Returns...) {
+				if param.Name == receiver {
+					issues = append(issues, fmt.Sprintf("%s.%s: parameter %q collides with the receiver name; configure another style receiver",
+						key, method.Name, receiver))
+				}
+			}
+		}
+	}
+
+	if g.options.Style.Constructor == ConstructorShort {
+		for dir, names := range constructors {
+			if len(names) > 1 {
+				if dir == "" {
+					dir = "."
+				}
+				issues = append(issues, fmt.Sprintf("%s: all generate New in %s; use the %s constructor style or give each implementation its own package",
+					strings.Join(names, ", "), dir, ConstructorInterface))
+			}
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStyle(t *testing.T) {
+	tests := []struct {
+		name        string
+		style       Style
+		constructor string
+		want        string // source of GetByID
+	}{
+		{"default", Style{}, `func NewUserRepository(db *sql.DB) UserRepository {
+	return &userRepository{
+		db: db,
+	}
+}`, `func (impl *userRepository) GetByID(ctx context.Context, id int64) (*User, error) {
+	query := "SELECT id, email, name FROM users WHERE id = $1"
+	var entity User
+	if err := impl.db.QueryRowContext(ctx, query, id).Scan(&entity.ID, &entity.Email, &entity.Name); err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	return &entity, nil
+}`},
+		{"receiver name", Style{Receiver: "r"}, "", `func (r *userRepository) GetByID(ctx context.Context, id int64) (*User, error) {
+	query := "SELECT id, email, name FROM users WHERE id = $1"
+	var entity User
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&entity.ID, &entity.Email, &entity.Name); err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	return &entity, nil
+}`},
+		{"short names, values and errors.Join", Style{Receiver: ReceiverShort, Constructor: ConstructorShort, ValueReceivers: true, ErrorWrapping: ErrorsJoin}, `func New(db *sql.DB) UserRepository {
+	return userRepository{
+		db: db,
+	}
+}`, `func (u userRepository) GetByID(ctx context.Context, id int64) (*User, error) {
+	query := "SELECT id, email, name FROM users WHERE id = $1"
+	var entity User
+	if err := u.db.QueryRowContext(ctx, query, id).Scan(&entity.ID, &entity.Email, &entity.Name); err != nil {
+		return nil, errors.Join(errors.New("failed to query users"), err)
+	}
+	return &entity, nil
+}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := generate(t, Options{Style: tt.style}, analyze(t, map[string]string{"user.go": userRepositorySource}))
+			content := files["user_repository.gen.go"]
+
+			if tt.constructor != "" {
+				name := "NewUserRepository"
+				if tt.style.Constructor == ConstructorShort {
+					name = "New"
+				}
+				if got := funcSource(t, content, name); got != tt.constructor {
+					t.Errorf("got:\n%s\n\nwant:\n%s", got, tt.constructor)
+				}
+			}
+			if got := funcSource(t, content, "GetByID"); got != tt.want {
+				t.Errorf("got:\n%s\n\nwant:\n%s", got, tt.want)
+			}
+
+			typeCheck(t, map[string]string{"user.go": userRepositorySource, "user_repository.gen.go": content})
+		})
+	}
+}
+
+func TestCommentStyle(t *testing.T) {
+	tests := []struct {
+		comments string
+		want     []string
+		dropped  []string
+	}{
+		{CommentsFull,
+			[]string{"// userRepository implements UserRepository interface\n", "// FindByAddress implements the FindByAddress method\n", "\t// TODO: Implement FindByAddress\n", "\t// Example database operation:\n"},
+			nil,
+		},
+		{CommentsMinimal,
+			[]string{"// userRepository implements UserRepository interface\n", "// FindByAddress implements the FindByAddress method\n", "\t// TODO: Implement FindByAddress\n"},
+			[]string{"\t// Example database operation:\n"},
+		},
+		{CommentsNone,
+			[]string{"\t// TODO: Implement FindByAddress\n"},
+			[]string{"// userRepository implements UserRepository interface\n", "// FindByAddress implements the FindByAddress method\n", "\t// Example database operation:\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.comments, func(t *testing.T) {
+			files := generate(t, Options{Style: Style{Comments: tt.comments}}, analyze(t, map[string]string{"user.go": userRepositorySource}))
+			content := files["user_repository.gen.go"]
+
+			if !strings.HasPrefix(content, "// Code generated by code-gen. DO NOT EDIT.\n") {
+				t.Errorf("file header dropped:\n%s", content)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("comment %q dropped:\n%s", want, content)
+				}
+			}
+			for _, dropped := range tt.dropped {
+				if strings.Contains(content, dropped) {
+					t.Errorf("comment %q kept:\n%s", dropped, content)
+				}
+			}
+		})
+	}
+}
+
+func TestStyleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   Style
+		wantErr bool
+	}{
+		{"defaults", Style{}, false},
+		{"all options", Style{Receiver: ReceiverShort, Constructor: ConstructorShort, ValueReceivers: true, ErrorWrapping: ErrorsJoin, Comments: CommentsNone}, false},
+		{"receiver identifier", Style{Receiver: "svc"}, false},
+		{"receiver not an identifier", Style{Receiver: "my-impl"}, true},
+		{"blank receiver", Style{Receiver: "_"}, true},
+		{"unknown constructor", Style{Constructor: "make"}, true},
+		{"unknown error wrapping", Style{ErrorWrapping: "pkg/errors"}, true},
+		{"unknown comment density", Style{Comments: "some"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.style.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"path"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// Component describes what is generated for an analyzed interface
+type Component struct {
+	Interface    string
+	Package      string
+	Layer        types.LayerType
+	Methods      int
+	File         string   // generated implementation, empty when Constructor is set
+	Constructor  string   // handwritten constructor used instead of generating one
+	Entity       string   // struct stored by a repository
+	Storage      string   // "sql" or "mongo" for repositories with an entity
+	Table        string   // SQL table or Mongo collection of the entity
+	Dependencies []string // parameters of the generated constructor
+}
+
+// Components describes the analyzed interfaces in generation order, without
+// generating any code
+func (g *Generator) Components(projectInfo *types.ProjectInfo) []Component {
+	var components []Component
+	for _, key := range g.sortedInterfaces(projectInfo) {
+		interfaceInfo := projectInfo.Interfaces[key]
+		component := Component{
+			Interface: interfaceInfo.Name,
+			Package:   interfaceInfo.Package,
+			Layer:     interfaceInfo.Layer,
+			Methods:   len(interfaceInfo.Methods),
+		}
+
+		if fn := g.handwrittenConstructor(key, projectInfo); fn != nil {
+			component.Constructor = fn.Name
+			components = append(components, component)
+			continue
+		}
+
+		target := g.implPackage(interfaceInfo, projectInfo)
+		component.File = path.Join(target.dir, g.generateFileName(interfaceInfo.Name, interfaceInfo.Layer))
+		if entity := g.repositoryEntity(key, interfaceInfo, projectInfo); entity != nil {
+			component.Entity, component.Table, component.Storage = entity.name, entity.table, "sql"
+			if entity.mongo {
+				component.Storage = "mongo"
+			}
+		}
+		component.Dependencies = g.generateDependencies(key, interfaceInfo, projectInfo)
+		components = append(components, component)
+	}
+	return components
+}
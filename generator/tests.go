@@ -0,0 +1,233 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// testDependency is a constructor argument of a generated implementation under test
+type testDependency struct {
+	name     string
+	mockType string // empty when the dependency is not mocked
+}
+
+// generateTest generates a table-driven test per method of the generated
+// implementation. Dependencies on analyzed interfaces are replaced by the
+// testify mocks returned in mocked; other dependencies are passed as nil.
+// The file is a scaffold the project completes.
+func (g *Generator) generateTest(key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo) (file *GeneratedFile, mocked []string) {
+	target := g.implPackage(interfaceInfo, projectInfo)
+	source := g.sourcePackage(interfaceInfo, projectInfo)
+	interfaceName := interfaceInfo.Name
+
+	testPkg := g.testPackage(target)
+	mocksPrefix := "mocks."
+	if testPkg.importPath == target.importPath {
+		mocksPrefix = ""
+	}
+	prefix := qualifier(source, testPkg)
+
+	fileName := path.Join(target.dir, strings.TrimSuffix(g.generateFileName(interfaceName, interfaceInfo.Layer), ".gen.go")+"_test.go")
+
+	var deps []testDependency
+	for _, dep := range g.generateDependencies(key, interfaceInfo, projectInfo) {
+		parts := strings.Fields(dep)
+		if len(parts) < 2 {
+			continue
+		}
+		dependency := testDependency{name: parts[0]}
+		if depKey, exists := g.analyzedInterface(parts[1], typeScope{dir: source.dir}, projectInfo); exists {
+			dependency.mockType = "*" + mocksPrefix + "Mock" + g.factoryName(depKey, projectInfo)
+			mocked = append(mocked, depKey)
+		}
+		deps = append(deps, dependency)
+	}
+
+	var mockParams, mockArgs, constructorArgs []string
+	for _, dep := range deps {
+		if dep.mockType == "" {
+			constructorArgs = append(constructorArgs, "nil")
+			continue
+		}
+		mockParams = append(mockParams, fmt.Sprintf("%s %s", dep.name, dep.mockType))
+		mockArgs = append(mockArgs, dep.name)
+		constructorArgs = append(constructorArgs, dep.name)
+	}
+	constructor := qualifier(target, testPkg) + g.constructorName(interfaceName)
+
+	// Repositories with runnable bodies need a real database connection
+	needsDatabase := g.repositoryEntity(key, interfaceInfo, projectInfo) != nil
+
+	imports := map[string]bool{"testing": true}
+	if target.importPath != testPkg.importPath {
+		imports[target.importPath] = true
+	}
+	if len(mockArgs) > 0 && mocksPrefix != "" {
+		imports[path.Join(g.baseImportPath(projectInfo), mocksDir)] = true
+	}
+
+	var body strings.Builder
+
+	for _, method := range interfaceInfo.Methods {
+		var args []string
+		for _, param := range method.Params {
+			arg := g.testArgument(param.Type, prefix, interfaceInfo, imports, projectInfo)
+			if arg == "" {
+				// Variadic parameters are called with no arguments
+				continue
+			}
+			args = append(args, arg)
+			g.addArgumentImports(arg, prefix, source, interfaceInfo, imports, projectInfo)
+		}
+
+		errIndex := -1
+		for i, ret := range method.Returns {
+			if ret.Type == "error" {
+				errIndex = i
+			}
+		}
+
+		call := fmt.Sprintf("impl.%s(%s)", method.Name, strings.Join(args, ", "))
+		if errIndex >= 0 {
+			results := make([]string, len(method.Returns))
+			for i := range results {
+				results[i] = "_"
+			}
+			results[errIndex] = "err"
+			call = fmt.Sprintf("%s := %s", strings.Join(results, ", "), call)
+		}
+
+		testName := fmt.Sprintf("Test%s_%s", interfaceName, method.Name)
+		body.WriteString(fmt.Sprintf("// %s tests %s.%s\n", testName, interfaceName, method.Name))
+		body.WriteString(fmt.Sprintf("func %s(t *testing.T) {\n", testName))
+		body.WriteString("\ttests := []struct {\n")
+		body.WriteString("\t\tname string\n")
+		body.WriteString(fmt.Sprintf("\t\tsetup func(%s)\n", strings.Join(mockParams, ", ")))
+		if errIndex >= 0 {
+			body.WriteString("\t\twantErr bool\n")
+		}
+		body.WriteString("\t}{\n")
+		body.WriteString("\t\t{\n")
+		body.WriteString("\t\t\tname: \"success\",\n")
+		body.WriteString(fmt.Sprintf("\t\t\tsetup: func(%s) {},\n", strings.Join(mockParams, ", ")))
+		body.WriteString("\t\t},\n")
+		body.WriteString("\t\t// TODO: Add test cases\n")
+		body.WriteString("\t}\n\n")
+
+		body.WriteString("\tfor _, tt := range tests {\n")
+		body.WriteString("\t\tt.Run(tt.name, func(t *testing.T) {\n")
+		if needsDatabase {
+			body.WriteString("\t\t\tt.Skip(\"TODO: Provide a test database\")\n\n")
+		}
+		for _, dep := range deps {
+			if dep.mockType != "" {
+				body.WriteString(fmt.Sprintf("\t\t\t%s := new(%s)\n", dep.name, strings.TrimPrefix(dep.mockType, "*")))
+			}
+		}
+		body.WriteString(fmt.Sprintf("\t\t\ttt.setup(%s)\n", strings.Join(mockArgs, ", ")))
+		body.WriteString(fmt.Sprintf("\t\t\timpl := %s(%s)\n\n", constructor, strings.Join(constructorArgs, ", ")))
+		body.WriteString(fmt.Sprintf("\t\t\t%s\n", call))
+		if errIndex >= 0 {
+			body.WriteString("\t\t\tif (err != nil) != tt.wantErr {\n")
+			body.WriteString(fmt.Sprintf("\t\t\t\tt.Errorf(\"%s() error = %%v, wantErr %%v\", err, tt.wantErr)\n", method.Name))
+			body.WriteString("\t\t\t}\n")
+		}
+		for _, dep := range deps {
+			if dep.mockType != "" {
+				body.WriteString(fmt.Sprintf("\t\t\t%s.AssertExpectations(t)\n", dep.name))
+			}
+		}
+		body.WriteString("\t\t})\n")
+		body.WriteString("\t}\n")
+		body.WriteString("}\n\n")
+	}
+
+	var content strings.Builder
+
+	g.writeScaffoldHeader(&content, testPkg.name)
+
+	content.WriteString("import (\n")
+	for _, imp := range importSpecs(imports) {
+		content.WriteString(fmt.Sprintf("\t%s\n", imp))
+	}
+	content.WriteString(")\n\n")
+
+	content.WriteString(body.String())
+
+	return &GeneratedFile{
+		Filename:  fileName,
+		Content:   content.String(),
+		LineCount: strings.Count(content.String(), "\n"),
+		Scaffold:  true,
+	}, mocked
+}
+
+// testPackage returns the package of the generated tests and benchmarks of
+// the implementations in target. Tests of importable packages are external so
+// they can import the mocks package, which itself imports the package under
+// test.
+func (g *Generator) testPackage(target goPackage) goPackage {
+	if target.name == "main" {
+		return target
+	}
+	return goPackage{dir: target.dir, name: target.name + "_test", importPath: target.importPath + "_test"}
+}
+
+// addArgumentImports adds the import paths of the packages referenced by arg,
+// a value passed in a generated test of the interface declared in source
+func (g *Generator) addArgumentImports(arg, prefix string, source goPackage, interfaceInfo *types.InterfaceInfo, imports map[string]bool, projectInfo *types.ProjectInfo) {
+	for _, match := range packageSelector.FindAllStringSubmatch(arg, -1) {
+		switch {
+		case match[1] == strings.TrimSuffix(prefix, "."):
+			imports[source.importPath] = true
+		case g.fileImports(interfaceInfo, projectInfo)[match[1]] != "":
+			imports[g.fileImports(interfaceInfo, projectInfo)[match[1]]] = true
+		}
+	}
+}
+
+// testArgument returns the value passed for a parameter of typeName, written
+// in the interface declared by interfaceInfo, in a generated test, adding the
+// imports it needs. Pointers to project structs get an empty struct, so
+// implementations can dereference them. Variadic parameters yield "".
+func (g *Generator) testArgument(typeName, prefix string, interfaceInfo *types.InterfaceInfo, imports map[string]bool, projectInfo *types.ProjectInfo) string {
+	switch {
+	case typeName == "context.Context":
+		imports["context"] = true
+		return "context.Background()"
+	case typeName == "http.ResponseWriter":
+		imports["net/http/httptest"] = true
+		return "httptest.NewRecorder()"
+	case typeName == "*http.Request":
+		imports["net/http"] = true
+		imports["net/http/httptest"] = true
+		return "httptest.NewRequest(http.MethodGet, \"/\", nil)"
+	case strings.HasPrefix(typeName, "..."):
+		return ""
+	}
+	if structName, pointer := strings.CutPrefix(typeName, "*"); pointer {
+		if _, exists := lookup(projectInfo.Structs, structName, interfaceScope(interfaceInfo), projectInfo); exists {
+			return "&" + qualifyType(structName, prefix) + "{}"
+		}
+	}
+	return g.generateZeroValue(qualifyType(typeName, prefix), projectInfo)
+}
+
+// generateTestMocks generates the mocks used by the generated tests
+func (g *Generator) generateTestMocks(mocked map[string]bool, projectInfo *types.ProjectInfo) []*GeneratedFile {
+	keys := make([]string, 0, len(mocked))
+	for key := range mocked {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var results []*GeneratedFile
+	for _, key := range keys {
+		results = append(results, g.generateMock(key, projectInfo.Interfaces[key], projectInfo))
+	}
+	return results
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateTest(t *testing.T) {
+	files := generate(t, Options{Tests: true}, analyze(t, map[string]string{"shop.go": shopSource}))
+
+	want := `func TestUserUseCase_Remove(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(repo *mocks.MockUserRepository)
+		wantErr bool
+	}{
+		{
+			name:  "success",
+			setup: func(repo *mocks.MockUserRepository) {},
+		},
+		// TODO: Add test cases
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockUserRepository)
+			tt.setup(repo)
+			impl := shop.NewUserUseCase(repo)
+
+			err := impl.Remove(context.Background(), 0)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Remove() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}`
+	useCaseTest := files["user_usecase_test.go"]
+	if got := funcSource(t, useCaseTest, "TestUserUseCase_Remove"); got != want {
+		t.Errorf("TestUserUseCase_Remove =\n%s\n\nwant:\n%s", got, want)
+	}
+	for _, want := range []string{
+		"// Scaffolded by code-gen. Edit freely: code-gen does not overwrite this file.\n\npackage shop_test\n",
+		// Variadic parameters are left out, named results are assigned
+		`_, err := impl.Register(context.Background(), "")`,
+		"_, _, err := impl.Watch(context.Background())",
+	} {
+		if !strings.Contains(useCaseTest, want) {
+			t.Errorf("user_usecase_test.go does not contain %s\n\ngot:\n%s", want, useCaseTest)
+		}
+	}
+	if strings.Contains(useCaseTest, "DO NOT EDIT") {
+		t.Errorf("scaffold is marked DO NOT EDIT:\n%s", useCaseTest)
+	}
+
+	// Methods without an error result have no wantErr field
+	handlerTest := funcSource(t, files["user_handler_test.go"], "TestUserHandler_Get")
+	for _, want := range []string{
+		"\t\tname  string\n\t\tsetup func(useCase *mocks.MockUserUseCase)\n\t}{",
+		"impl.Get(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, \"/\", nil))",
+	} {
+		if !strings.Contains(handlerTest, want) {
+			t.Errorf("TestUserHandler_Get does not contain %s\n\ngot:\n%s", want, handlerTest)
+		}
+	}
+
+	// Repositories with runnable bodies need a database
+	repositoryTest := funcSource(t, files["user_repository_test.go"], "TestUserRepository_GetByID")
+	if !strings.Contains(repositoryTest, `t.Skip("TODO: Provide a test database")`) {
+		t.Errorf("TestUserRepository_GetByID does not skip without a database:\n%s", repositoryTest)
+	}
+
+	// The mocked dependencies are generated alongside
+	for _, name := range []string{"mocks/user_repository.gen.go", "mocks/user_usecase.gen.go"} {
+		if _, exists := files[name]; !exists {
+			t.Errorf("mock %s not generated", name)
+		}
+	}
+}
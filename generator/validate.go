@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// predeclared lists Go's predeclared identifiers that generated code must not shadow
+var predeclared = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true, "complex64": true,
+	"complex128": true, "error": true, "float32": true, "float64": true, "int": true,
+	"int8": true, "int16": true, "int32": true, "int64": true, "rune": true,
+	"string": true, "uint": true, "uint8": true, "uint16": true, "uint32": true,
+	"uint64": true, "uintptr": true, "true": true, "false": true, "iota": true,
+	"nil": true, "append": true, "cap": true, "clear": true, "close": true,
+	"complex": true, "copy": true, "delete": true, "imag": true, "len": true,
+	"make": true, "max": true, "min": true, "new": true, "panic": true,
+	"print": true, "println": true, "real": true, "recover": true,
+}
+
+// generatedPackages lists the package names imported by generated files
+var generatedPackages = []string{"context", "sql", "errors", "fmt", "json", "http", "wire", "fiber", "gin", "echo"}
+
+// generatedNames lists the top-level identifiers declared by the factory and wire files
+var generatedNames = []string{"Factory", "NewFactory", "ProviderSet"}
+
+// ValidationError lists the naming problems that would make generated code uncompilable
+type ValidationError struct {
+	Issues []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d naming conflicts found:\n  - %s", len(e.Issues), strings.Join(e.Issues, "\n  - "))
+}
+
+// Validate checks that the names derived from analyzed interfaces are valid,
+// unique Go identifiers before any code is generated
+func (g *Generator) Validate(projectInfo *types.ProjectInfo) error {
+	var issues []string
+
+	packages := make(map[string]bool)
+	for _, pkg := range generatedPackages {
+		packages[pkg] = true
+	}
+	for alias := range projectInfo.Imports {
+		packages[alias] = true
+	}
+
+	files := make(map[string][]string)
+	factories := make(map[string][]string)
+
+	for _, key := range g.sortedInterfaces(projectInfo) {
+		interfaceInfo := projectInfo.Interfaces[key]
+		// Every interface gets a factory method, injector and mock named
+		// after it, prefixed with its package name when the name is shared
+		name := g.factoryName(key, projectInfo)
+		factories[name] = append(factories[name], key)
+
+		// Interfaces with a handwritten constructor are not generated
+		if g.handwrittenConstructor(key, projectInfo) != nil {
+			continue
+		}
+
+		interfaceName := interfaceInfo.Name
+		target := g.implPackage(interfaceInfo, projectInfo)
+		structName := g.generateStructName(interfaceName)
+		suggestion := g.suggestName(interfaceName, interfaceInfo.Layer)
+
+		switch {
+		case token.IsKeyword(structName):
+			issues = append(issues, fmt.Sprintf("%s: implementation name %q is a Go keyword; rename the interface (e.g. %s)",
+				key, structName, suggestion))
+		case predeclared[structName]:
+			issues = append(issues, fmt.Sprintf("%s: implementation name %q shadows a predeclared identifier; rename the interface (e.g. %s)",
+				key, structName, suggestion))
+		case packages[structName]:
+			issues = append(issues, fmt.Sprintf("%s: implementation name %q collides with package %s; rename the interface (e.g. %s)",
+				key, structName, structName, suggestion))
+		}
+
+		if existing, exists := projectInfo.Structs[types.Key(target.dir, structName)]; exists {
+			issues = append(issues, fmt.Sprintf("%s: implementation name %q is already declared in %s; rename the struct or the interface (e.g. %s)",
+				key, structName, existing.FilePath, suggestion))
+		}
+
+		fileName := path.Join(target.dir, g.generateFileName(interfaceName, interfaceInfo.Layer))
+		files[fileName] = append(files[fileName], key)
+	}
+
+	// The factory and wire files declare their names in the di package
+	diDir := g.layerPackage(diPackageKey, projectInfo).dir
+	for _, name := range generatedNames {
+		if _, exists := projectInfo.Interfaces[types.Key(diDir, name)]; exists {
+			issues = append(issues, fmt.Sprintf("%s: collides with the generated %s; rename the interface (e.g. App%s)", name, name, name))
+		}
+		if existing, exists := projectInfo.Structs[types.Key(diDir, name)]; exists {
+			issues = append(issues, fmt.Sprintf("%s: struct declared in %s collides with the generated %s; rename the struct (e.g. App%s)",
+				name, existing.FilePath, name, name))
+		}
+	}
+
+	issues = append(issues, g.styleIssues(projectInfo)...)
+
+	for fileName, names := range files {
+		if len(names) > 1 {
+			sort.Strings(names)
+			issues = append(issues, fmt.Sprintf("%s: all generate %s; rename all but one of them",
+				strings.Join(names, ", "), fileName))
+		}
+	}
+
+	for name, keys := range factories {
+		if len(keys) > 1 {
+			sort.Strings(keys)
+			issues = append(issues, fmt.Sprintf("%s: all generate New%s and Mock%s; rename all but one of them",
+				strings.Join(keys, ", "), name, name))
+		}
+	}
+
+	if len(issues) > 0 {
+		sort.Strings(issues)
+		return &ValidationError{Issues: issues}
+	}
+
+	return nil
+}
+
+// suggestName proposes an interface name whose implementation name is safe
+func (g *Generator) suggestName(interfaceName string, layer types.LayerType) string {
+	suffixes := map[types.LayerType]string{
+		types.RepositoryLayer: "Repo",
+		types.UseCaseLayer:    "UseCase",
+		types.HandlerLayer:    "Handler",
+		types.ServiceLayer:    "Service",
+	}
+
+	suffix := suffixes[layer]
+	if strings.HasSuffix(interfaceName, suffix) {
+		return interfaceName + "Impl"
+	}
+	return interfaceName + suffix
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/navyarakshakarya/code-gen/logger"
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+func TestGenerateFileName(t *testing.T) {
+	g := New(logger.New(false, true), Options{})
+
+	tests := []struct {
+		interfaceName string
+		layer         types.LayerType
+		want          string
+	}{
+		{"UserRepository", types.RepositoryLayer, "user_repository.gen.go"},
+		{"OrderUseCase", types.UseCaseLayer, "order_usecase.gen.go"},
+		{"Repository", types.RepositoryLayer, "repository.gen.go"},
+		{"Handler", types.HandlerLayer, "handler.gen.go"},
+	}
+	for _, tt := range tests {
+		if got := g.generateFileName(tt.interfaceName, tt.layer); got != tt.want {
+			t.Errorf("generateFileName(%s) = %s, want %s", tt.interfaceName, got, tt.want)
+		}
+	}
+}
+
+func TestValidateSharedFactoryNames(t *testing.T) {
+	g := New(logger.New(false, true), Options{})
+	projectInfo := &types.ProjectInfo{
+		ModuleName: "example.com/shop",
+		Packages:   map[string]string{"v1/user": "user", "v2/user": "user"},
+		Interfaces: map[string]*types.InterfaceInfo{
+			"v1/user.Repository": {Name: "Repository", Package: "user", FilePath: "v1/user/user.go", Layer: types.RepositoryLayer},
+			"v2/user.Repository": {Name: "Repository", Package: "user", FilePath: "v2/user/user.go", Layer: types.RepositoryLayer},
+		},
+		Structs: map[string]*types.StructInfo{},
+	}
+
+	var validationErr *ValidationError
+	if err := g.Validate(projectInfo); !errors.As(err, &validationErr) {
+		t.Fatalf("Validate() = %v, want a ValidationError", err)
+	}
+	if len(validationErr.Issues) != 1 || !strings.Contains(validationErr.Issues[0], "NewUserRepository") {
+		t.Errorf("issues = %q, want one about NewUserRepository", validationErr.Issues)
+	}
+
+	// Package directories that differ in name keep the factories apart
+	projectInfo.Packages["v2/user"] = "userv2"
+	projectInfo.Interfaces["v2/user.Repository"].Package = "userv2"
+	if err := g.Validate(projectInfo); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
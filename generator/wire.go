@@ -0,0 +1,325 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/navyarakshakarya/code-gen/types"
+)
+
+// WireFile is the name of the file declaring the Wire injectors
+const WireFile = "wire.go"
+
+// configName is the configuration type passed to the factory and injectors
+const configName = "Config"
+
+// wellKnownImports maps the package names used by generated dependencies to
+// their import paths
+var wellKnownImports = map[string]string{
+	"context": "context",
+	"sql":     "database/sql",
+	"mongo":   "go.mongodb.org/mongo-driver/mongo",
+}
+
+// dependencyNames are the names of well-known external dependencies
+var dependencyNames = map[string]string{
+	"*sql.DB":         "db",
+	"context.Context": "ctx",
+}
+
+// configPackage returns the package declaring the Config type and whether the
+// project declares it; otherwise a placeholder is generated in the di package.
+// A Config in the project root, the only one, or the one of a package named
+// config is used.
+func (g *Generator) configPackage(projectInfo *types.ProjectInfo) (goPackage, bool) {
+	key, exists := lookup(projectInfo.Structs, configName, typeScope{dir: "."}, projectInfo)
+	if !exists {
+		key, exists = lookup(projectInfo.Structs, "config."+configName, typeScope{dir: "."}, projectInfo)
+	}
+	if exists {
+		structInfo := projectInfo.Structs[key]
+		return g.declPackage(structInfo.Package, structInfo.FilePath, projectInfo), true
+	}
+	return g.layerPackage(diPackageKey, projectInfo), false
+}
+
+// configRef returns the Config type as referred to from the package from
+func (g *Generator) configRef(projectInfo *types.ProjectInfo, from goPackage) string {
+	pkg, _ := g.configPackage(projectInfo)
+	return qualifier(pkg, from) + configName
+}
+
+// generateConfig generates the placeholder Config used when the project does
+// not declare one
+func (g *Generator) generateConfig(projectInfo *types.ProjectInfo) *GeneratedFile {
+	var content strings.Builder
+
+	diPkg := g.layerPackage(diPackageKey, projectInfo)
+	g.writeFileHeader(&content, diPkg.name)
+
+	content.WriteString("// Config holds the application configuration passed to NewFactory.\n")
+	content.WriteString("// Declare your own Config struct and delete this file to replace it.\n")
+	content.WriteString("type Config struct{}\n")
+
+	return &GeneratedFile{
+		Filename:  path.Join(diPkg.dir, "config.gen.go"),
+		Content:   content.String(),
+		LineCount: strings.Count(content.String(), "\n"),
+	}
+}
+
+// generateWireIntegration generates the Wire provider set and an injector per
+// handler, declared in a file only built by Wire itself
+func (g *Generator) generateWireIntegration(projectInfo *types.ProjectInfo) (*GeneratedFile, error) {
+	diPkg := g.layerPackage(diPackageKey, projectInfo)
+	imports := map[string]bool{"github.com/google/wire": true}
+
+	var body strings.Builder
+
+	// Provider set
+	body.WriteString("// ProviderSet is the Wire provider set for dependency injection\n")
+	body.WriteString("var ProviderSet = wire.NewSet(\n")
+	for _, key := range g.sortedInterfaces(projectInfo) {
+		body.WriteString(fmt.Sprintf("\t%s,\n", g.constructorRef(key, projectInfo.Interfaces[key], projectInfo, diPkg)))
+	}
+	body.WriteString("\tNewFactory,\n")
+	body.WriteString(")\n\n")
+
+	// Wire injector functions
+	for _, key := range g.sortedInterfaces(projectInfo) {
+		interfaceInfo := projectInfo.Interfaces[key]
+		if interfaceInfo.Layer == types.HandlerLayer {
+			g.writeWireInjector(&body, key, interfaceInfo, projectInfo, diPkg, imports)
+		}
+	}
+
+	var content strings.Builder
+
+	g.writeConstrainedFileHeader(&content, diPkg.name, "wireinject")
+
+	content.WriteString("// Run `go generate` or `wire` in this directory to generate wire_gen.go\n")
+	content.WriteString("// from the injectors below.\n\n")
+
+	for _, spec := range usedImports(g.diImports(diPkg, projectInfo), body.String()) {
+		imports[strings.Trim(spec, "\"")] = true
+	}
+	content.WriteString("import (\n")
+	for _, spec := range importSpecs(imports) {
+		content.WriteString(fmt.Sprintf("\t%s\n", spec))
+	}
+	content.WriteString(")\n\n")
+
+	content.WriteString(body.String())
+
+	return &GeneratedFile{
+		Filename:  path.Join(diPkg.dir, WireFile),
+		Content:   content.String(),
+		LineCount: strings.Count(content.String(), "\n"),
+	}, nil
+}
+
+// dependencySet collects the providers needed to build analyzed interfaces
+// and the external dependencies nothing analyzed provides
+type dependencySet struct {
+	providers []string          // in dependency order
+	params    []string          // "name type" of the external dependencies
+	types     map[string]string // external dependency type -> name
+	names     map[string]bool
+	visited   map[string]bool
+	todos     []string
+	cleanup   bool // a provider returns a cleanup function
+}
+
+// factoryDependencies collects the external dependencies of all analyzed
+// interfaces, which become the fields of the factory, and their imports
+func (g *Generator) factoryDependencies(projectInfo *types.ProjectInfo) (*dependencySet, map[string]bool) {
+	diPkg := g.layerPackage(diPackageKey, projectInfo)
+	set := newDependencySet()
+	imports := make(map[string]bool)
+	for _, key := range g.sortedInterfaces(projectInfo) {
+		g.collectProviders(set, key, projectInfo, diPkg, imports)
+	}
+	return set, imports
+}
+
+// needsConfig reports whether any constructor depends on the Config type
+func (g *Generator) needsConfig(projectInfo *types.ProjectInfo) bool {
+	set, _ := g.factoryDependencies(projectInfo)
+	config := g.configRef(projectInfo, g.layerPackage(diPackageKey, projectInfo))
+	for typeName := range set.types {
+		if strings.TrimPrefix(typeName, "*") == config {
+			return true
+		}
+	}
+	return false
+}
+
+// newDependencySet returns an empty dependencySet
+func newDependencySet() *dependencySet {
+	return &dependencySet{
+		types:   make(map[string]string),
+		names:   make(map[string]bool),
+		visited: make(map[string]bool),
+	}
+}
+
+// writeWireInjector writes an injector building the interface with the given
+// key from exactly the providers it depends on, taking everything else as
+// arguments
+func (g *Generator) writeWireInjector(content *strings.Builder, key string, interfaceInfo *types.InterfaceInfo, projectInfo *types.ProjectInfo, from goPackage, imports map[string]bool) {
+	injector := newDependencySet()
+	g.collectProviders(injector, key, projectInfo, from, imports)
+
+	interfaceName := interfaceInfo.Name
+	name := g.factoryName(key, projectInfo)
+	results := g.interfaceRef(interfaceInfo, projectInfo, from)
+	zero := "nil"
+	if injector.cleanup {
+		results += ", func()"
+		zero += ", nil"
+	}
+
+	content.WriteString(fmt.Sprintf("// Initialize%s creates a %s with all of its dependencies\n", name, interfaceName))
+	content.WriteString(fmt.Sprintf("func Initialize%s(%s) (%s, error) {\n", name, strings.Join(injector.params, ", "), results))
+	for _, todo := range injector.todos {
+		content.WriteString(fmt.Sprintf("\t%s\n", todo))
+	}
+	content.WriteString(fmt.Sprintf("\twire.Build(%s)\n", strings.Join(injector.providers, ", ")))
+	content.WriteString(fmt.Sprintf("\treturn %s, nil\n", zero))
+	content.WriteString("}\n\n")
+}
+
+// collectProviders adds the provider of the interface with the given key
+// after the providers and arguments of its dependencies
+func (g *Generator) collectProviders(set *dependencySet, key string, projectInfo *types.ProjectInfo, from goPackage, imports map[string]bool) {
+	if set.visited[key] {
+		return
+	}
+	set.visited[key] = true
+
+	interfaceInfo := projectInfo.Interfaces[key]
+	interfaceName := interfaceInfo.Name
+	constructor := g.constructorRef(key, interfaceInfo, projectInfo, from)
+
+	// Dependencies of handwritten constructors are inferred from their parameters
+	if fn := g.handwrittenConstructor(key, projectInfo); fn != nil {
+		prefix := qualifier(g.declPackage(fn.Package, fn.FilePath, projectInfo), from)
+		for _, param := range fn.Params {
+			g.collectDependency(set, param.Type, prefix, funcScope(fn), projectInfo, from, imports, true)
+		}
+
+		set.providers = append(set.providers, constructor)
+		if len(fn.Returns) > 0 && !g.returnsInterface(fn, key, projectInfo) {
+			// Bind the interface to the concrete type the constructor returns,
+			// which can only be named from another package when exported
+			concrete := strings.TrimPrefix(fn.Returns[0].Type, "*")
+			if prefix != "" && !token.IsExported(concrete) {
+				set.todos = append(set.todos, fmt.Sprintf("// TODO: %s returns unexported %s; bind it to %s in its package",
+					fn.Name, fn.Returns[0].Type, interfaceName))
+			} else {
+				set.providers = append(set.providers, fmt.Sprintf("wire.Bind(new(%s), new(%s))",
+					g.interfaceRef(interfaceInfo, projectInfo, from), qualifyType(fn.Returns[0].Type, prefix)))
+			}
+		}
+		if len(fn.Returns) > 1 && strings.HasPrefix(fn.Returns[1].Type, "func(") {
+			set.cleanup = true
+		}
+		return
+	}
+
+	source := g.sourcePackage(interfaceInfo, projectInfo)
+	prefix := qualifier(source, from)
+	for _, dep := range g.generateDependencies(key, interfaceInfo, projectInfo) {
+		if parts := strings.Fields(dep); len(parts) >= 2 {
+			g.collectDependency(set, strings.Join(parts[1:], " "), prefix, typeScope{dir: source.dir}, projectInfo, from, imports, false)
+		}
+	}
+	set.providers = append(set.providers, constructor)
+}
+
+// collectDependency resolves a constructor parameter of typeName, written in
+// scope: analyzed interfaces come from their providers, parameter structs
+// declared in the project are built by wire.Struct when expandStructs is set,
+// and anything else becomes an external dependency
+func (g *Generator) collectDependency(set *dependencySet, typeName, prefix string, scope typeScope, projectInfo *types.ProjectInfo, from goPackage, imports map[string]bool, expandStructs bool) {
+	if key, exists := g.analyzedInterface(typeName, scope, projectInfo); exists {
+		g.collectProviders(set, key, projectInfo, from, imports)
+		return
+	}
+
+	structName := strings.TrimPrefix(typeName, "*")
+	structKey, isStruct := lookup(projectInfo.Structs, structName, scope, projectInfo)
+	if structInfo := projectInfo.Structs[structKey]; isStruct && expandStructs && structInfo.Name != configName {
+		qualified := qualifyType(structName, prefix)
+		if set.visited[qualified] {
+			return
+		}
+		set.visited[qualified] = true
+
+		for _, field := range structInfo.Fields {
+			if field.Embedded || field.Name == "" {
+				continue
+			}
+			g.collectDependency(set, field.Type, prefix, typeScope{dir: path.Dir(structInfo.FilePath)}, projectInfo, from, imports, false)
+		}
+		set.providers = append(set.providers, fmt.Sprintf("wire.Struct(new(%s), \"*\")", qualified))
+		return
+	}
+
+	qualified := g.qualifyDependency(typeName, prefix, projectInfo, from)
+	if _, exists := set.types[qualified]; exists {
+		return
+	}
+
+	for _, match := range packageSelector.FindAllStringSubmatch(qualified, -1) {
+		if importPath := projectInfo.Imports[match[1]]; importPath != "" {
+			imports[importPath] = true
+		} else if importPath := wellKnownImports[match[1]]; importPath != "" {
+			imports[importPath] = true
+		}
+	}
+
+	name := dependencyName(qualified)
+	for i := 2; set.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", strings.TrimRight(name, "0123456789"), i)
+	}
+	set.names[name] = true
+	set.types[qualified] = name
+	set.params = append(set.params, fmt.Sprintf("%s %s", name, qualified))
+}
+
+// qualifyDependency qualifies a dependency type declared in the package of
+// prefix for use in the package from, resolving Config to the type the factory
+// and injectors use
+func (g *Generator) qualifyDependency(typeName, prefix string, projectInfo *types.ProjectInfo, from goPackage) string {
+	if structName := strings.TrimPrefix(typeName, "*"); structName == configName {
+		return strings.TrimSuffix(typeName, structName) + g.configRef(projectInfo, from)
+	}
+	return qualifyType(typeName, prefix)
+}
+
+// dependencyName derives the name of an external dependency from its type
+func dependencyName(typeName string) string {
+	if name, ok := dependencyNames[typeName]; ok {
+		return name
+	}
+
+	name := strings.TrimLeft(typeName, "*[]")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		return "dep"
+	}
+
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	name = string(runes)
+	if token.IsKeyword(name) || predeclared[name] {
+		name += "Dep"
+	}
+	return name
+}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/navyarakshakarya/code-gen/logger"
+)
+
+// zeroValueSource declares named types of every kind in package shop and its
+// domain subpackage
+var zeroValueSource = map[string]string{
+	"shop.go": `package shop
+
+import (
+	"context"
+	"time"
+
+	"example.com/shop/domain"
+)
+
+type Status int
+type Label string
+type Tags []string
+type Callback func(error)
+type Events chan string
+type Point struct{ X, Y int }
+type Location Point
+type Pair[T any] struct{ First, Second T }
+type Clock interface{ Now() time.Time }
+
+type EventRepository interface {
+	Stream(ctx context.Context) (<-chan Point, chan<- error, Events)
+	Hooks() (func(), Callback, func(int) error)
+	Values() (Status, Label, Tags, Point, Location, Pair[int], Clock)
+	Composite() ([2]int, struct{ N int }, map[string]int, interface{ Close() error }, any)
+	Qualified() (time.Time, time.Duration, domain.Money, domain.Currency, context.CancelFunc)
+}
+`,
+	"domain/money.go": `package domain
+
+type Currency string
+
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+`,
+}
+
+func TestGenerateZeroValue(t *testing.T) {
+	projectInfo := analyze(t, zeroValueSource)
+	g := New(logger.New(false, true), Options{})
+
+	tests := []struct {
+		typeName string
+		want     string
+	}{
+		{"string", `""`},
+		{"bool", "false"},
+		{"float64", "0"},
+		{"error", "nil"},
+		{"any", "nil"},
+		{"*Point", "nil"},
+		{"[]Point", "nil"},
+		{"map[string]int", "nil"},
+		{"chan string", "nil"},
+		{"chan<- error", "nil"},
+		{"<-chan Point", "nil"},
+		{"func()", "nil"},
+		{"func(int) error", "nil"},
+		{"interface{ Close() error }", "nil"},
+		{"[2]int", "[2]int{}"},
+		{"struct{ N int }", "struct{ N int }{}"},
+		{"Status", "0"},
+		{"Label", `""`},
+		{"Tags", "nil"},
+		{"Callback", "nil"},
+		{"Events", "nil"},
+		{"Point", "Point{}"},
+		{"Location", "Location{}"},
+		{"Pair[int]", "Pair[int]{}"},
+		{"Clock", "nil"},
+		{"time.Time", "time.Time{}"},
+		{"time.Duration", "0"},
+		{"domain.Money", "domain.Money{}"},
+		{"domain.Currency", `""`},
+		{"context.CancelFunc", "*new(context.CancelFunc)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			if got := g.generateZeroValue(tt.typeName, projectInfo); got != tt.want {
+				t.Errorf("generateZeroValue(%q) = %s, want %s", tt.typeName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateZeroValueReturns(t *testing.T) {
+	files := generate(t, Options{}, analyze(t, zeroValueSource))
+	content := files["event_repository.gen.go"]
+	if formatted, err := format.Source([]byte(content)); err != nil || string(formatted) != content {
+		t.Fatalf("event_repository.gen.go is not gofmt-clean (%v):\n%s", err, content)
+	}
+
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{"Stream", "\treturn nil, nil, nil\n"},
+		{"Hooks", "\treturn nil, nil, nil\n"},
+		{"Values", "\treturn 0, \"\", nil, Point{}, Location{}, Pair[int]{}, nil\n"},
+		{"Composite", "\treturn [2]int{}, struct{ N int }{}, nil, nil, nil\n"},
+		{"Qualified", "\treturn time.Time{}, 0, domain.Money{}, \"\", *new(context.CancelFunc)\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := funcSource(t, content, tt.method); !strings.Contains(got, tt.want) {
+				t.Errorf("%s does not return\n%s\ngot:\n%s", tt.method, tt.want, got)
+			}
+		})
+	}
+}
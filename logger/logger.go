@@ -10,11 +10,20 @@ import (
 // Logger provides structured logging with different levels
 type Logger struct {
 	verbose bool
+	plain   bool
 }
 
-// New creates a new logger instance
-func New(verbose bool) *Logger {
-	return &Logger{verbose: verbose}
+// New creates a new logger instance. Plain loggers mark levels with ASCII
+// words instead of symbols, for terminals and CI logs without Unicode support.
+func New(verbose, plain bool) *Logger {
+	return &Logger{verbose: verbose, plain: plain}
+}
+
+// plainLevels are the ASCII replacements of the level symbols
+var plainLevels = map[string]string{
+	"✓": "OK",
+	"⚠": "WARN",
+	"✗": "ERROR",
 }
 
 // Info logs informational messages (only in verbose mode)
@@ -46,6 +55,9 @@ func (l *Logger) Fatal(format string, args ...interface{}) {
 }
 
 func (l *Logger) log(level, format string, args ...interface{}) {
+	if l.plain && plainLevels[level] != "" {
+		level = plainLevels[level]
+	}
 	timestamp := time.Now().Format("15:04:05")
 	message := fmt.Sprintf(format, args...)
 
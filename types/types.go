@@ -1,13 +1,40 @@
 package types
 
+import "strings"
+
 // ProjectInfo contains all analyzed project information
 type ProjectInfo struct {
 	ModuleName  string
-	PackageName string
+	PackageName string // package of the project root
 	ProjectDir  string
-	Interfaces  map[string]*InterfaceInfo
-	Structs     map[string]*StructInfo
-	Imports     map[string]string // package -> import path
+	Packages    map[string]string // slash-separated directory -> package name
+
+	// Declarations are keyed by Key, so same-named types of different
+	// packages are kept apart
+	Interfaces map[string]*InterfaceInfo
+	Structs    map[string]*StructInfo
+	Types      map[string]*TypeInfo // other named types, e.g. type Status string
+	Functions  map[string]*FuncInfo // constructors (New*)
+	Imports    map[string]string    // package -> import path
+}
+
+// Key returns the key of a declaration in the ProjectInfo maps: its name
+// qualified by the slash-separated directory of its package, such as
+// internal/user.Repository, or just its name in the project root
+func Key(dir, name string) string {
+	if dir == "" || dir == "." {
+		return name
+	}
+	return dir + "." + name
+}
+
+// SplitKey returns the package directory and the name of a declaration key
+func SplitKey(key string) (dir, name string) {
+	i := strings.LastIndex(key, ".")
+	if i < 0 {
+		return ".", key
+	}
+	return key[:i], key[i+1:]
 }
 
 // InterfaceInfo represents an analyzed interface
@@ -17,8 +44,9 @@ type InterfaceInfo struct {
 	FilePath          string
 	Methods           []MethodInfo
 	Layer             LayerType
-	RelatedInterfaces []string
+	RelatedInterfaces []string // keys of the interfaces sharing the base name
 	Comments          []string
+	Imports           map[string]string // imports of the declaring file: package -> import path
 }
 
 // StructInfo represents an analyzed struct
@@ -27,9 +55,29 @@ type StructInfo struct {
 	Package  string
 	FilePath string
 	Fields   []FieldInfo
+	Methods  []MethodInfo // exported methods declared on the struct
 	Comments []string
 }
 
+// TypeInfo represents an analyzed named type that is neither a struct nor an
+// interface
+type TypeInfo struct {
+	Name       string
+	Package    string
+	FilePath   string
+	Underlying string
+}
+
+// FuncInfo represents an analyzed top-level constructor function (New*)
+type FuncInfo struct {
+	Name     string
+	Package  string
+	FilePath string
+	Params   []ParamInfo
+	Returns  []ParamInfo
+	Imports  map[string]string // imports of the declaring file: package -> import path
+}
+
 // MethodInfo represents a method in an interface
 type MethodInfo struct {
 	Name       string
@@ -38,6 +86,7 @@ type MethodInfo struct {
 	HasContext bool
 	HasError   bool
 	Comments   []string
+	Skip       bool // annotated with //codegen:skip: implemented by hand
 }
 
 // ParamInfo represents a parameter or return value
@@ -0,0 +1,25 @@
+package types
+
+import "testing"
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		dir, name, key string
+	}{
+		{".", "UserRepo", "UserRepo"},
+		{"", "UserRepo", "UserRepo"},
+		{"internal/user", "Repository", "internal/user.Repository"},
+		{"api.v1", "Handler", "api.v1.Handler"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if key := Key(tt.dir, tt.name); key != tt.key {
+				t.Errorf("Key(%q, %q) = %q, want %q", tt.dir, tt.name, key, tt.key)
+			}
+			dir, name := SplitKey(tt.key)
+			if want := tt.dir; (want == "" && dir != ".") || (want != "" && dir != want) || name != tt.name {
+				t.Errorf("SplitKey(%q) = %q, %q", tt.key, dir, name)
+			}
+		})
+	}
+}